@@ -0,0 +1,60 @@
+package darksky
+
+import "time"
+
+// Severity is how severe an alert is, as reported by the API. From least to most severe:
+//
+//	advisory  an individual should be aware of potentially severe weather
+//	watch     an individual should prepare for potentially severe weather
+//	warning   an individual should take immediate action to protect themselves
+type Severity string
+
+const (
+	AdvisorySeverity Severity = "advisory"
+	WatchSeverity    Severity = "watch"
+	WarningSeverity  Severity = "warning"
+)
+
+// severityRanks orders the known severities, higher being more severe.
+var severityRanks = map[Severity]int{AdvisorySeverity: 1, WatchSeverity: 2, WarningSeverity: 3}
+
+// Rank returns the position of the severity in the ordering advisory < watch < warning, from 1 to 3.
+// Unknown or missing severities rank below advisory, at 0.
+func (s Severity) Rank() int {
+	return severityRanks[s]
+}
+
+// IsActive reports whether the alert is in effect at t: issued at or before t, and expiring after
+// it. Alerts without an issue time are taken as already issued, and alerts without an expiry time,
+// which the API doesn't always send, as open-ended.
+func (a Alert) IsActive(t time.Time) bool {
+	return a.Time <= t.Unix() && (a.Expires == 0 || t.Unix() < a.Expires)
+}
+
+// HighestSeverityAlert returns the most severe alert active at the given time, ranked by
+// Severity.Rank. Ties go to the alert listed first. The bool is false when no alert is active.
+// Safe to call on a nil Forecast.
+func (f *Forecast) HighestSeverityAlert(at time.Time) (Alert, bool) {
+	if f == nil {
+		return Alert{}, false
+	}
+
+	var highest Alert
+	var found bool
+
+	for _, a := range f.Alerts {
+		if a.IsActive(at) && (!found || a.Severity.Rank() > highest.Severity.Rank()) {
+			highest, found = a, true
+		}
+	}
+
+	return highest, found
+}
+
+// Duration returns how long the alert has left until it expires, measured from the reference time
+// from, typically the current time. The duration is negative once the alert has expired.
+//
+//	fmt.Printf("expires in %v", alert.Duration(time.Now()).Round(time.Minute))
+func (a Alert) Duration(from time.Time) time.Duration {
+	return time.Unix(a.Expires, 0).Sub(from)
+}
@@ -0,0 +1,73 @@
+package darksky
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlert_Duration(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+	alert := forecast.Alerts[0]
+	expires := time.Unix(alert.Expires, 0)
+
+	if d := alert.Duration(expires.Add(-2 * time.Hour)); d != 2*time.Hour {
+		t.Errorf("Expected 2h until expiry, got %v.", d)
+	}
+
+	if d := alert.Duration(expires); d != 0 {
+		t.Errorf("Expected 0 at expiry, got %v.", d)
+	}
+
+	if d := alert.Duration(expires.Add(90 * time.Minute)); d != -90*time.Minute {
+		t.Errorf("Expected a negative duration once expired, got %v.", d)
+	}
+}
+
+func TestForecast_HighestSeverityAlert(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if forecast.Alerts[0].Time != 1451356620 {
+		t.Errorf("Expected the alert issue time to be parsed, got %v.", forecast.Alerts[0].Time)
+	}
+
+	forecast.Alerts = []Alert{
+		{Title: "Flood Advisory", Severity: AdvisorySeverity, Time: 1000, Expires: 5000},
+		{Title: "Winter Storm Warning", Severity: WarningSeverity, Time: 3000, Expires: 6000},
+		{Title: "Flood Watch", Severity: WatchSeverity, Time: 1000, Expires: 4000},
+		{Title: "Special Statement", Time: 0, Expires: 9000},
+		{Title: "Air Quality Alert", Severity: AdvisorySeverity, Time: 9500},
+	}
+
+	tests := []struct {
+		at       int64
+		expected string
+	}{
+		{1000, "Flood Watch"},
+		{3500, "Winter Storm Warning"},
+		{5500, "Winter Storm Warning"},
+		{7000, "Special Statement"},
+		{9500, "Air Quality Alert"},
+		{1 << 40, "Air Quality Alert"},
+	}
+
+	for _, test := range tests {
+		alert, ok := forecast.HighestSeverityAlert(time.Unix(test.at, 0))
+		if !ok || alert.Title != test.expected {
+			t.Errorf("Expected %v at %v, got %v, %v.", test.expected, test.at, alert.Title, ok)
+		}
+	}
+
+	if _, ok := forecast.HighestSeverityAlert(time.Unix(9000, 0)); ok {
+		t.Error("Expected no alert once they have all expired, and before the open-ended one is issued.")
+	}
+
+	if _, ok := (*Forecast)(nil).HighestSeverityAlert(time.Unix(1000, 0)); ok {
+		t.Error("Expected no alert for a nil forecast.")
+	}
+}
+
+func TestSeverity_Rank(t *testing.T) {
+	if !(Severity("").Rank() < AdvisorySeverity.Rank() && AdvisorySeverity.Rank() < WatchSeverity.Rank() && WatchSeverity.Rank() < WarningSeverity.Rank()) {
+		t.Error("Expected severities to be ordered advisory < watch < warning.")
+	}
+}
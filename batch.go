@@ -0,0 +1,169 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// GetTimeRangeWithContext errors
+const (
+	StepInvalid = "step is not valid, must be greater than zero"
+)
+
+// GetAllMap calls GetWithContext for every request concurrently, with at most concurrency in flight
+// at once, returning the responses under the same keys as their requests, such as city names.
+// Requests not started before ctx is done are given ctx.Err() as their error.
+func GetAllMap(ctx context.Context, reqs map[string]*ForecastRequest, concurrency int) map[string]ForecastResponse {
+	keys := make([]string, 0, len(reqs))
+	for k := range reqs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	requests := make([]*ForecastRequest, len(keys))
+	for i, k := range keys {
+		requests[i] = reqs[k]
+	}
+
+	responses := map[string]ForecastResponse{}
+	for i, fr := range getBatch(ctx, requests, concurrency) {
+		responses[keys[i]] = fr
+	}
+
+	return responses
+}
+
+// GetMultiLang requests the forecast in each of the given languages, using f as a template, with
+// at most concurrency requests in flight at once, returning the responses keyed by language. Each
+// language is a separate API call, so this multiplies API usage by the number of languages.
+func (f *ForecastRequest) GetMultiLang(langs []Lang, concurrency int) map[Lang]ForecastResponse {
+	var unique []Lang
+	var requests []*ForecastRequest
+
+	seen := map[Lang]bool{}
+	for _, l := range langs {
+		if !seen[l] {
+			seen[l] = true
+			unique = append(unique, l)
+			requests = append(requests, f.Clone().WithLang(l))
+		}
+	}
+
+	responses := map[Lang]ForecastResponse{}
+	for i, fr := range getBatch(context.Background(), requests, concurrency) {
+		responses[unique[i]] = fr
+	}
+
+	return responses
+}
+
+// GetAllStream calls GetWithContext for every request, with at most concurrency in flight at once,
+// sending each response on the returned channel as soon as it completes, so results can be
+// processed without waiting for the whole batch. Responses arrive in completion order, use
+// ForecastResponse.Request to tell which request each is for. The channel is closed once every
+// response has been sent, or once ctx is done, in which case outstanding requests are cancelled and
+// their responses may not be sent. A caller that stops reading early must cancel ctx, so the
+// requests still in flight aren't left blocked sending.
+func GetAllStream(ctx context.Context, reqs []*ForecastRequest, concurrency int) <-chan ForecastResponse {
+	out := make(chan ForecastResponse)
+
+	go func() {
+		defer close(out)
+
+		runBatch(ctx, reqs, concurrency, func(i int, fr ForecastResponse) {
+			select {
+			case out <- fr:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return out
+}
+
+// TimeRangeResult is the Time Machine response for a single step of GetTimeRangeWithContext.
+type TimeRangeResult struct {
+	Time time.Time
+	ForecastResponse
+}
+
+// Canceled reports whether the step failed because the context was done, either before its
+// request was started or while it was in flight.
+func (r TimeRangeResult) Canceled() bool {
+	return errors.Is(r.Error, context.Canceled) || errors.Is(r.Error, context.DeadlineExceeded)
+}
+
+// GetTimeRangeWithContext makes a Time Machine request for every step from start to end inclusive,
+// using f as a template, with at most concurrency requests in flight at once. Results are returned
+// in step order. Once ctx is done, outstanding requests are cancelled and steps not yet started
+// are skipped; both report Canceled, while steps that already completed keep their forecast.
+func (f *ForecastRequest) GetTimeRangeWithContext(ctx context.Context, start time.Time, end time.Time, step time.Duration, concurrency int) ([]TimeRangeResult, error) {
+	if step <= 0 {
+		return nil, errors.New(StepInvalid)
+	}
+
+	var results []TimeRangeResult
+	var requests []*ForecastRequest
+
+	for t := start; !t.After(end); t = t.Add(step) {
+		results = append(results, TimeRangeResult{Time: t})
+		requests = append(requests, f.Clone().WithTime(t.Unix()))
+	}
+
+	for i, fr := range getBatch(ctx, requests, concurrency) {
+		results[i].ForecastResponse = fr
+	}
+
+	return results, nil
+}
+
+// getBatch runs requests with at most concurrency in flight, returning their responses in the
+// same order. Requests not started before ctx is done are given ctx.Err() as their error.
+func getBatch(ctx context.Context, requests []*ForecastRequest, concurrency int) []ForecastResponse {
+	responses := make([]ForecastResponse, len(requests))
+
+	runBatch(ctx, requests, concurrency, func(i int, fr ForecastResponse) {
+		responses[i] = fr
+	})
+
+	return responses
+}
+
+// runBatch runs requests with at most concurrency in flight, calling fn with the index and response
+// of each as it completes. Requests not started before ctx is done are given ctx.Err() as their
+// error. fn may be called concurrently, and runBatch returns once every call to fn has returned.
+func runBatch(ctx context.Context, requests []*ForecastRequest, concurrency int, fn func(i int, fr ForecastResponse)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, req := range requests {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fn(i, ForecastResponse{Request: req, Error: ctx.Err()})
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, req *ForecastRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i, req.GetWithContext(ctx))
+		}(i, req)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,208 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForecastRequest_GetTimeRangeWithContext(t *testing.T) {
+	start := time.Date(2015, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	usingTestServer(validForecastHandler, func(testURL string) {
+		results, err := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).
+			GetTimeRangeWithContext(context.Background(), start, start.Add(72*time.Hour), 24*time.Hour, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 4 {
+			t.Fatalf("Expected 4 results, got %d.", len(results))
+		}
+
+		for i, r := range results {
+			if r.Error != nil || r.Canceled() {
+				t.Errorf("Expected step %d to succeed, got %v.", i, r.Error)
+			}
+
+			if !r.Time.Equal(start.Add(time.Duration(i) * 24 * time.Hour)) {
+				t.Errorf("Expected step %d to be in order, got %v.", i, r.Time)
+			}
+		}
+	})
+}
+
+func TestForecastRequest_GetTimeRangeWithContext_Canceled(t *testing.T) {
+	start := time.Date(2015, 12, 1, 12, 0, 0, 0, time.UTC)
+	first := strconv.FormatInt(start.Unix(), 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, ","+first) {
+			validForecastHandler(resp, req)
+			return
+		}
+
+		// Cancel once the second step is in flight, and hold it until the client gives up.
+		cancel()
+		<-req.Context().Done()
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		results, err := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).
+			GetTimeRangeWithContext(ctx, start, start.Add(72*time.Hour), 24*time.Hour, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if results[0].Error != nil || results[0].Forecast.Currently == nil {
+			t.Errorf("Expected the first step to complete, got %v.", results[0].Error)
+		}
+
+		for i, r := range results[1:] {
+			if !r.Canceled() {
+				t.Errorf("Expected step %d to be canceled, got %v.", i+1, r.Error)
+			}
+		}
+	})
+}
+
+func TestForecastRequest_GetTimeRangeWithContext_InvalidStep(t *testing.T) {
+	_, err := MakeRequest(key, 41.8781, -87.6297).GetTimeRangeWithContext(context.Background(), time.Now(), time.Now(), 0, 1)
+	if err == nil || err.Error() != StepInvalid {
+		t.Errorf("Expected a zero step to be invalid, got %v.", err)
+	}
+}
+
+func TestGetAllMap(t *testing.T) {
+	usingTestServer(validForecastHandler, func(testURL string) {
+		responses := GetAllMap(context.Background(), map[string]*ForecastRequest{
+			"Chicago": MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL),
+			"Sydney":  MakeRequest(key, -33.8688, 151.2093).WithBaseURL(testURL),
+			"Invalid": MakeRequest(key, 100, 0).WithBaseURL(testURL),
+		}, 2)
+
+		if len(responses) != 3 {
+			t.Fatalf("Expected 3 responses, got %d.", len(responses))
+		}
+
+		for _, city := range []string{"Chicago", "Sydney"} {
+			if responses[city].Error != nil || responses[city].Forecast.Currently == nil {
+				t.Errorf("Expected a forecast for %v, got %v.", city, responses[city].Error)
+			}
+		}
+
+		if err := responses["Invalid"].Error; err == nil || err.Error() != LatitudeInvalid {
+			t.Errorf("Expected the invalid request's error under its key, got %v.", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		responses = GetAllMap(ctx, map[string]*ForecastRequest{
+			"Chicago": MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL),
+		}, 2)
+
+		if err := responses["Chicago"].Error; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected a cancelled context to be reported, got %v.", err)
+		}
+	})
+}
+
+func TestForecastRequest_GetMultiLang(t *testing.T) {
+	var hits int32
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		responses := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).
+			GetMultiLang([]Lang{English, French, German, French}, 2)
+
+		if len(responses) != 3 || hits != 3 {
+			t.Fatalf("Expected one call per distinct language, got %v responses from %v calls.", len(responses), hits)
+		}
+
+		for _, l := range []Lang{English, French, German} {
+			if responses[l].Error != nil || responses[l].Forecast.Currently == nil {
+				t.Errorf("Expected a forecast in %v, got %v.", l, responses[l].Error)
+			}
+		}
+	})
+}
+
+func TestGetAllStream(t *testing.T) {
+	usingTestServer(validForecastHandler, func(testURL string) {
+		var reqs []*ForecastRequest
+		for i := 0; i < 10; i++ {
+			reqs = append(reqs, MakeRequest(key, float64(i), -87.6297).WithBaseURL(testURL))
+		}
+
+		reqs = append(reqs, MakeRequest(key, 100, 0).WithBaseURL(testURL))
+
+		seen := map[*ForecastRequest]bool{}
+
+		for resp := range GetAllStream(context.Background(), reqs, 3) {
+			if seen[resp.Request] {
+				t.Errorf("Expected one response per request, got a second for %v.", resp.Request.Lat)
+			}
+
+			seen[resp.Request] = true
+
+			if resp.Request.Lat == 100 {
+				if resp.Error == nil {
+					t.Error("Expected the invalid request to fail.")
+				}
+			} else if resp.Error != nil {
+				t.Errorf("Expected a forecast for %v, got %v.", resp.Request.Lat, resp.Error)
+			}
+		}
+
+		if len(seen) != len(reqs) {
+			t.Errorf("Expected %v responses before the channel closed, got %v.", len(reqs), len(seen))
+		}
+	})
+}
+
+func TestGetAllStream_Canceled(t *testing.T) {
+	usingTestServer(validForecastHandler, func(testURL string) {
+		var reqs []*ForecastRequest
+		for i := 0; i < 10; i++ {
+			reqs = append(reqs, MakeRequest(key, float64(i), -87.6297).WithBaseURL(testURL))
+		}
+
+		idle := http.DefaultTransport.(*http.Transport).CloseIdleConnections
+		idle()
+		before := runtime.NumGoroutine()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := GetAllStream(ctx, reqs, 3)
+
+		// Stop reading after the first response, leaving the rest of the batch unsent.
+		<-stream
+		cancel()
+
+		for i := 0; i < 500 && runtime.NumGoroutine() > before; i++ {
+			idle()
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if n := runtime.NumGoroutine(); n > before {
+			t.Errorf("Expected no goroutines to be left blocked, had %v before and %v after.", before, n)
+		}
+
+		if _, ok := <-stream; ok {
+			t.Error("Expected the channel to be closed once the context was cancelled.")
+		}
+	})
+}
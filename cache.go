@@ -0,0 +1,106 @@
+package darksky
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores forecasts keyed by request URL. A Client configured with a Cache returns a cached
+// Forecast without making a network call, and stores each successfully fetched Forecast. How long
+// an entry stays valid is up to the implementation. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (*Forecast, bool)
+	Set(key string, forecast *Forecast)
+}
+
+// MemoryCache is an in-memory Cache where entries expire after a fixed TTL. It is safe for
+// concurrent use by multiple goroutines.
+type MemoryCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	forecast *Forecast
+	expires  time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache whose entries expire after ttl.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{ttl: ttl, entries: map[string]memoryCacheEntry{}}
+}
+
+// Get returns the forecast stored for key, if it hasn't expired.
+func (c *MemoryCache) Get(key string) (*Forecast, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !Now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.forecast, true
+}
+
+// Set stores forecast for key, replacing any existing entry and resetting its TTL.
+func (c *MemoryCache) Set(key string, forecast *Forecast) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryCacheEntry{forecast: forecast, expires: Now().Add(c.ttl)}
+}
+
+// ConditionalEntry is a Forecast stored along with the validators needed to make a conditional
+// request for it.
+type ConditionalEntry struct {
+	Forecast     Forecast
+	ETag         string
+	LastModified string
+}
+
+// ConditionalCache stores forecasts keyed by request URL, along with the ETag and Last-Modified
+// headers of the response they came from. A Client configured with a ConditionalCache sends
+// If-None-Match and If-Modified-Since on subsequent requests for the same URL, and returns the
+// cached Forecast when the API responds with 304 Not Modified. Implementations must be safe for
+// concurrent use, and can be backed by an external store such as Redis.
+type ConditionalCache interface {
+	Get(url string) (ConditionalEntry, bool)
+	Set(url string, entry ConditionalEntry)
+}
+
+// MemoryConditionalCache is an in-memory ConditionalCache. It is safe for concurrent use by
+// multiple goroutines. Entries are never evicted, so it's best suited to polling a fixed set of
+// locations.
+type MemoryConditionalCache struct {
+	mu      sync.RWMutex
+	entries map[string]ConditionalEntry
+}
+
+// NewMemoryConditionalCache creates an empty MemoryConditionalCache.
+func NewMemoryConditionalCache() *MemoryConditionalCache {
+	return &MemoryConditionalCache{entries: map[string]ConditionalEntry{}}
+}
+
+// Get returns the entry stored for url, if any.
+func (c *MemoryConditionalCache) Get(url string) (ConditionalEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[url]
+	return entry, ok
+}
+
+// Set stores entry for url, replacing any existing entry.
+func (c *MemoryConditionalCache) Set(url string, entry ConditionalEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[url] = entry
+}
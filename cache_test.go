@@ -0,0 +1,105 @@
+package darksky
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_WithConditionalCache(t *testing.T) {
+	var hits, notModified int
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		hits++
+
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			resp.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		resp.Header().Set("ETag", `"v1"`)
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		client := NewClient(key).WithConditionalCache(NewMemoryConditionalCache())
+
+		for i := 0; i < 3; i++ {
+			resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(testURL).Get()
+			if resp.Error != nil {
+				t.Fatal(resp.Error)
+			}
+
+			if resp.Forecast.Timezone != "America/Chicago" || len(resp.Forecast.Alerts) != 3 {
+				t.Errorf("Expected the full forecast on call %v, got %+v.", i+1, resp.Forecast)
+			}
+		}
+
+		if hits != 3 || notModified != 2 {
+			t.Errorf("Expected 3 calls with 2 not modified, got %v and %v.", hits, notModified)
+		}
+	})
+}
+
+func TestClient_WithCache(t *testing.T) {
+	var hits int
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		hits++
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		cache := NewMemoryCache(time.Minute)
+		client := NewClient(key).WithCache(cache)
+
+		for i := 0; i < 3; i++ {
+			resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(testURL).Get()
+			if resp.Error != nil {
+				t.Fatal(resp.Error)
+			}
+
+			if len(resp.Forecast.Alerts) != 3 {
+				t.Errorf("Expected the full forecast on call %v.", i+1)
+			}
+		}
+
+		if hits != 1 {
+			t.Errorf("Expected 1 call to the API, got %v.", hits)
+		}
+
+		client.MakeRequest(41.8781, -87.6297).WithBaseURL(testURL).WithUnits(SI).Get()
+
+		if hits != 2 {
+			t.Errorf("Expected a different URL to miss the cache, got %v calls.", hits)
+		}
+	})
+}
+
+func TestMemoryCache_TTL(t *testing.T) {
+	cache := NewMemoryCache(0)
+	cache.Set("a", &Forecast{})
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected an entry with a zero TTL to be expired.")
+	}
+
+	cache = NewMemoryCache(time.Hour)
+	cache.Set("a", &Forecast{Timezone: "America/Chicago"})
+
+	if f, ok := cache.Get("a"); !ok || f.Timezone != "America/Chicago" {
+		t.Error("Expected the entry to be cached.")
+	}
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected a missing key not to be found.")
+	}
+
+	defer func(now func() time.Time) { Now = now }(Now)
+	Now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the entry to expire after its TTL.")
+	}
+}
@@ -0,0 +1,52 @@
+package darksky
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithRecorder(t *testing.T) {
+	dir := t.TempDir()
+
+	ts := httptest.NewServer(validForecastHandler)
+
+	resp := NewClient("secret_key").WithBaseURL(ts.URL).WithRecorder(dir).MakeRequest(41.8781, -87.6297).Get()
+	if resp.Error != nil {
+		t.Fatal(resp.Error)
+	}
+
+	ts.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 recording, got %v.", files)
+	}
+
+	data, _ := ioutil.ReadFile(files[0])
+	if strings.Contains(string(data), "secret_key") {
+		t.Error("Expected the API key to be removed from the recording.")
+	}
+
+	// Replay with a different key, with the server gone.
+	client := NewClient("other_key").WithBaseURL(ts.URL).WithReplayer(dir)
+
+	replayed := client.MakeRequest(41.8781, -87.6297).Get()
+	if replayed.Error != nil {
+		t.Fatal(replayed.Error)
+	}
+
+	if replayed.Forecast.Currently.Time != resp.Forecast.Currently.Time || replayed.APICallCount != 1 {
+		t.Errorf("Expected the recorded response, got %+v.", replayed.Forecast.Currently)
+	}
+
+	missing := client.MakeRequest(40.7128, -74.0060).Get()
+
+	var netErr *NetworkError
+	if !errors.As(missing.Error, &netErr) || !strings.Contains(missing.Error.Error(), "no recorded response") {
+		t.Errorf("Expected a NetworkError for a request without a recording, got %v.", missing.Error)
+	}
+}
@@ -0,0 +1,289 @@
+package darksky
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ErrRateLimited is returned by Get, without making a network call, once the daily limit
+// configured on a Client has been reached.
+var ErrRateLimited = errors.New("daily API call limit reached")
+
+// APIKeyEnv is the environment variable NewClientFromEnv reads the API key from.
+const APIKeyEnv = "DARKSKY_API_KEY"
+
+// CurrentlyMissing is the error returned by CurrentConditions when the response has no currently block.
+const CurrentlyMissing = "response did not include current conditions"
+
+// GeocoderRequired is the error returned by ForecastByPlace when no geocoder has been set.
+const GeocoderRequired = "geocoder is required, set one with WithGeocoder"
+
+// Geocoder resolves a place name, such as "Chicago, IL", to a latitude and longitude.
+type Geocoder func(place string) (lat float64, lng float64, err error)
+
+// Client holds configuration shared by every request made with a single API key. Requests
+// created with Client.MakeRequest go through the Client when Get is called. A Client is safe
+// for concurrent use by multiple goroutines.
+type Client struct {
+	Key string
+
+	baseURL     string
+	httpClient  *http.Client
+	cache       Cache
+	conditional ConditionalCache
+	geocoder    Geocoder
+	compact     bool
+	display     Units
+	recordDir   string
+	replayDir   string
+	logger      func(RequestLog)
+
+	mu         sync.Mutex
+	dailyLimit int
+	calls      int
+	pending    int
+	day        string
+}
+
+// NewClient creates a Client for the given API key.
+func NewClient(key string) *Client {
+	return &Client{Key: key}
+}
+
+// NewClientFromEnv creates a Client using the API key in the DARKSKY_API_KEY environment variable,
+// returning an error if it is unset or empty.
+func NewClientFromEnv() (*Client, error) {
+	key := os.Getenv(APIKeyEnv)
+	if key == "" {
+		return nil, errors.New(APIKeyEnv + " is not set")
+	}
+
+	return NewClient(key), nil
+}
+
+// MakeRequest creates a new ForecastRequest using the Client's key and base URL, with the same
+// defaults as the package level MakeRequest.
+func (c *Client) MakeRequest(latitude float64, longitude float64) *ForecastRequest {
+	r := MakeRequest(c.Key, latitude, longitude)
+	r.client = c
+
+	if c.baseURL != "" {
+		r.baseURL = c.baseURL
+	}
+
+	if c.compact {
+		r.Exclude = append([]string{}, compactExclude...)
+	}
+
+	return r
+}
+
+// WithDisplayUnits makes Get convert every forecast retrieved by the Client's requests to the
+// target units system with ConvertUnits, such as for requests made with AUTO so the API picks
+// units suited to each location while everything is displayed consistently. Forecasts already in
+// the target units are left untouched. When the flags block was excluded the request's Units are
+// assumed, and AUTO forecasts without flags can't be converted. An empty target disables it.
+func (c *Client) WithDisplayUnits(target Units) *Client {
+	c.display = target
+	return c
+}
+
+// compactExclude are the blocks excluded from requests by compact mode.
+var compactExclude = []string{string(MinutelyBlock), string(FlagsBlock)}
+
+// WithCompactMode makes every request created by the Client exclude the minutely and flags blocks
+// by default, to reduce the size of responses on metered connections. Individual requests can
+// still ask for them with WithExclude, WithOnly or WithAllBlocks. Without flags, the units the
+// API resolved for an AUTO request aren't reported.
+func (c *Client) WithCompactMode(compact bool) *Client {
+	c.compact = compact
+	return c
+}
+
+// Ping checks that the API is reachable and accepts the Client's key, by requesting the forecast
+// for a fixed position with every block excluded. The Client's cache is bypassed, so each Ping
+// costs one API call. The error is the same as ForecastResponse.Error would be for a failed Get.
+func (c *Client) Ping(ctx context.Context) error {
+	r := c.MakeRequest(0, 0).WithOnly()
+	r.skipCache = true
+
+	return r.GetWithContext(ctx).Error
+}
+
+// WithBaseURL sets the base URL for every request made with the Client, such as an internal mirror
+// of the API. Calling WithBaseURL on an individual request takes precedence over the Client's.
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	c.baseURL = baseURL
+	return c
+}
+
+// CurrentConditions retrieves only the currently block for the given position, excluding every
+// other block to keep the response small.
+func (c *Client) CurrentConditions(latitude float64, longitude float64) (DataPoint, error) {
+	resp := c.MakeRequest(latitude, longitude).WithOnly(CurrentlyBlock).Get()
+	if resp.Error != nil {
+		return DataPoint{}, resp.Error
+	}
+
+	if resp.Forecast.Currently == nil {
+		return DataPoint{}, errors.New(CurrentlyMissing)
+	}
+
+	return *resp.Forecast.Currently, nil
+}
+
+// ForecastByPlace geocodes place using the Client's Geocoder, then retrieves the current forecast
+// for the resulting position. Errors from the geocoder are returned in the response unchanged.
+func (c *Client) ForecastByPlace(place string) ForecastResponse {
+	if c.geocoder == nil {
+		return ForecastResponse{Error: errors.New(GeocoderRequired)}
+	}
+
+	lat, lng, err := c.geocoder(place)
+	if err != nil {
+		return ForecastResponse{Error: err}
+	}
+
+	return c.MakeRequest(lat, lng).Get()
+}
+
+// WithGeocoder sets the function ForecastByPlace uses to turn place names into coordinates. No
+// geocoder is bundled with the package, so any geocoding service can be plugged in.
+func (c *Client) WithGeocoder(geocoder Geocoder) *Client {
+	c.geocoder = geocoder
+	return c
+}
+
+// WithDailyLimit stops the Client from making more than limit API calls per day. The count is
+// taken from the X-Forecast-API-Calls header of each response, so it is shared with any other
+// usage of the same key, and resets along with the header at midnight UTC. Once the limit is
+// reached Get returns ErrRateLimited without making a network call. A limit of 0 disables it.
+func (c *Client) WithDailyLimit(limit int) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dailyLimit = limit
+	return c
+}
+
+// WithInsecureSkipVerify disables verification of the server's TLS certificate when skip is true,
+// for testing against an internal mirror with a self-signed certificate. This is unsafe for
+// production use, as it allows the connection to be intercepted.
+//
+// Only the TLS config of the Client's transport is changed, on a copy of the transport and HTTP
+// client, so other settings such as timeouts are kept. Nothing is changed when the transport
+// already verifies as asked, or isn't an *http.Transport.
+func (c *Client) WithInsecureSkipVerify(skip bool) *Client {
+	hc := c.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return c
+	}
+
+	if current := transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify; current == skip {
+		return c
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	transport.TLSClientConfig.InsecureSkipVerify = skip
+
+	client := *hc
+	client.Transport = transport
+
+	c.httpClient = &client
+	return c
+}
+
+// WithCache makes the Client consult cache before each network call, and store every forecast it
+// successfully retrieves. Cache hits don't count towards the daily limit. The Forecast returned
+// from a cache hit shares its blocks with the cached copy, so it should be treated as read only.
+func (c *Client) WithCache(cache Cache) *Client {
+	c.cache = cache
+	return c
+}
+
+// WithConditionalCache enables conditional requests using the given cache. The Forecast returned
+// for a 304 Not Modified response shares its blocks with the cached copy, so it should be treated
+// as read only.
+func (c *Client) WithConditionalCache(cache ConditionalCache) *Client {
+	c.conditional = cache
+	return c
+}
+
+// client returns the http.Client to make requests with. Safe to call on a nil Client.
+func (c *Client) client() *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+
+	hc := c.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	if c.recordDir != "" || c.replayDir != "" {
+		return &http.Client{Transport: &cassette{next: hc, recordDir: c.recordDir, replayDir: c.replayDir}}
+	}
+
+	return hc
+}
+
+// reserve claims one API call against the daily limit, returning ErrRateLimited when none are left.
+// Calls in flight are counted so concurrent requests can't overshoot the limit.
+func (c *Client) reserve() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dailyLimit <= 0 {
+		return nil
+	}
+
+	if today := Now().UTC().Format("2006-01-02"); today != c.day {
+		c.day = today
+		c.calls = 0
+	}
+
+	if c.calls+c.pending >= c.dailyLimit {
+		return ErrRateLimited
+	}
+
+	c.pending++
+	return nil
+}
+
+// release completes a call claimed by reserve. callCount is the value of the API calls header,
+// or 0 when it wasn't present, in which case the call is counted locally if received is true. A call
+// that never got an HTTP response, such as one that failed to connect, isn't counted.
+func (c *Client) release(callCount int, received bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dailyLimit <= 0 {
+		return
+	}
+
+	c.pending--
+
+	if callCount > 0 {
+		c.calls = callCount
+	} else if received {
+		c.calls++
+	}
+}
@@ -0,0 +1,340 @@
+package darksky
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WithDailyLimit(t *testing.T) {
+	var hits int32
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		resp.Header().Add(APICallsHeader, strconv.Itoa(int(n)+8))
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		client := NewClient(key).WithDailyLimit(10)
+
+		for i := 0; i < 2; i++ {
+			resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(testURL).Get()
+			if resp.Error != nil {
+				t.Fatal(resp.Error)
+			}
+		}
+
+		resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(testURL).Get()
+		if resp.Error != ErrRateLimited {
+			t.Errorf("Expected ErrRateLimited once the limit was reached, got %v.", resp.Error)
+		}
+
+		if hits != 2 {
+			t.Errorf("Expected 2 calls to the API, got %v.", hits)
+		}
+
+		// Simulate the day rolling over at midnight UTC.
+		defer func(now func() time.Time) { Now = now }(Now)
+		Now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+
+		resp = client.MakeRequest(41.8781, -87.6297).WithBaseURL(testURL).Get()
+		if resp.Error != nil {
+			t.Errorf("Expected a reset call count to allow requests, got %v.", resp.Error)
+		}
+	})
+}
+
+func TestClient_WithDailyLimit_Unreachable(t *testing.T) {
+	ts := httptest.NewServer(validForecastHandler)
+	ts.Close()
+
+	client := NewClient(key).WithDailyLimit(1)
+
+	for i := 0; i < 3; i++ {
+		resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(ts.URL).Get()
+
+		var netErr *NetworkError
+		if !errors.As(resp.Error, &netErr) {
+			t.Fatalf("Expected a NetworkError for an unreachable server, got %v.", resp.Error)
+		}
+	}
+
+	if client.calls != 0 {
+		t.Errorf("Expected calls that never reached the API not to be counted, got %v.", client.calls)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	defer os.Setenv(APIKeyEnv, os.Getenv(APIKeyEnv))
+
+	os.Setenv(APIKeyEnv, "env_key")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.Key != "env_key" || client.MakeRequest(41.8781, -87.6297).Key != "env_key" {
+		t.Errorf("Expected the key to be read from %v, was %v.", APIKeyEnv, client.Key)
+	}
+
+	os.Unsetenv(APIKeyEnv)
+
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Errorf("Expected an error when %v is unset.", APIKeyEnv)
+	}
+}
+
+func TestClient_WithInsecureSkipVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(validForecastHandler)
+	defer ts.Close()
+
+	resp := NewClient(key).MakeRequest(41.8781, -87.6297).WithBaseURL(ts.URL).Get()
+	if resp.Error == nil {
+		t.Error("Expected a self-signed certificate to be rejected by default.")
+	}
+
+	resp = NewClient(key).WithInsecureSkipVerify(true).MakeRequest(41.8781, -87.6297).WithBaseURL(ts.URL).Get()
+	if resp.Error != nil {
+		t.Errorf("Expected a self-signed certificate to be accepted, got %v.", resp.Error)
+	}
+
+	if client := NewClient(key).WithInsecureSkipVerify(false); client.httpClient != nil {
+		t.Error("Expected the HTTP client to be left alone when verification isn't skipped.")
+	}
+
+	client := NewClient(key)
+	client.httpClient = &http.Client{Timeout: 5 * time.Second}
+	client.WithInsecureSkipVerify(true)
+
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected the HTTP client's timeout to be kept, got %v.", client.httpClient.Timeout)
+	}
+
+	if tlsConfig := http.DefaultTransport.(*http.Transport).TLSClientConfig; tlsConfig != nil && tlsConfig.InsecureSkipVerify {
+		t.Error("Expected http.DefaultTransport not to be modified.")
+	}
+
+	if resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(ts.URL).Get(); resp.Error != nil {
+		t.Errorf("Expected a self-signed certificate to be accepted, got %v.", resp.Error)
+	}
+
+	client.WithInsecureSkipVerify(false)
+
+	if resp := client.MakeRequest(41.8781, -87.6297).WithBaseURL(ts.URL).Get(); resp.Error == nil {
+		t.Error("Expected verification to be restored.")
+	}
+
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected the HTTP client's timeout to be kept, got %v.", client.httpClient.Timeout)
+	}
+}
+
+func TestClient_CurrentConditions(t *testing.T) {
+	var query url.Values
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		client := NewClient(key)
+		client.httpClient = redirectClient(testURL)
+
+		dp, err := client.CurrentConditions(41.8781, -87.6297)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dp.Time != 1451362625 {
+			t.Errorf("Expected the currently data point, got time %v.", dp.Time)
+		}
+
+		if query.Get("exclude") != "minutely,hourly,daily,alerts,flags" {
+			t.Errorf("Expected every other block to be excluded, got %q.", query.Get("exclude"))
+		}
+	})
+}
+
+func TestClient_ForecastByPlace(t *testing.T) {
+	var path string
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		client := NewClient(key)
+		client.httpClient = redirectClient(testURL)
+
+		resp := client.ForecastByPlace("Chicago")
+		if resp.Error == nil || resp.Error.Error() != GeocoderRequired {
+			t.Errorf("Expected a missing geocoder to be an error, got %v.", resp.Error)
+		}
+
+		notFound := errors.New("place not found")
+
+		client.WithGeocoder(func(place string) (float64, float64, error) {
+			if place != "Chicago" {
+				return 0, 0, notFound
+			}
+
+			return 41.8781, -87.6297, nil
+		})
+
+		resp = client.ForecastByPlace("Chicago")
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if path != "/forecast/test_key/41.8781,-87.6297" {
+			t.Errorf("Expected the geocoded position to be requested, got %v.", path)
+		}
+
+		if resp = client.ForecastByPlace("Atlantis"); resp.Error != notFound {
+			t.Errorf("Expected the geocoder error to be returned, got %v.", resp.Error)
+		}
+	})
+}
+
+func TestClient_WithBaseURL(t *testing.T) {
+	client := NewClient(key).WithBaseURL("https://mirror.example.com/forecast")
+
+	reqURL, _ := client.MakeRequest(41.8781, -87.6297).URL()
+	if reqURL != "https://mirror.example.com/forecast/test_key/41.8781,-87.6297?lang=en&units=us" {
+		t.Errorf("Expected the client's base URL to be used, got %v.", reqURL)
+	}
+
+	reqURL, _ = client.MakeRequest(41.8781, -87.6297).WithBaseURL("https://other.example.com/forecast").URL()
+	if reqURL != "https://other.example.com/forecast/test_key/41.8781,-87.6297?lang=en&units=us" {
+		t.Errorf("Expected the request's base URL to take precedence, got %v.", reqURL)
+	}
+
+	reqURL, _ = NewClient(key).MakeRequest(41.8781, -87.6297).URL()
+	if reqURL != "https://api.darksky.net/forecast/test_key/41.8781,-87.6297?lang=en&units=us" {
+		t.Errorf("Expected the default base URL without one set, got %v.", reqURL)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	var hits int32
+	var query url.Values
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		query = req.URL.Query()
+		resp.Write([]byte(`{"latitude":0,"longitude":0,"timezone":"Etc/GMT","offset":0}`))
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		client := NewClient(key).WithBaseURL(testURL).WithCache(NewMemoryCache(time.Hour))
+
+		for i := 0; i < 2; i++ {
+			if err := client.Ping(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if hits != 2 {
+			t.Errorf("Expected every ping to reach the API, got %v calls.", hits)
+		}
+
+		if query.Get("exclude") != "currently,minutely,hourly,daily,alerts,flags" {
+			t.Errorf("Expected every block to be excluded, got %q.", query.Get("exclude"))
+		}
+	})
+
+	usingTestServer(errorForecastHandler, func(testURL string) {
+		err := NewClient(key).WithBaseURL(testURL).Ping(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != 500 {
+			t.Errorf("Expected an *APIError, got %v.", err)
+		}
+	})
+}
+
+func TestClient_WithCompactMode(t *testing.T) {
+	client := NewClient(key).WithCompactMode(true)
+
+	req := client.MakeRequest(41.8781, -87.6297)
+	if !reflect.DeepEqual(req.Exclude, []string{"minutely", "flags"}) {
+		t.Errorf("Expected minutely and flags to be excluded, got %v.", req.Exclude)
+	}
+
+	req.Exclude[0] = "hourly"
+
+	if req = client.MakeRequest(41.8781, -87.6297); req.Exclude[0] != "minutely" {
+		t.Error("Expected each request to get its own Exclude slice.")
+	}
+
+	if req = client.MakeRequest(41.8781, -87.6297).WithAllBlocks(); len(req.Exclude) != 0 {
+		t.Errorf("Expected the request to be able to override compact mode, got %v.", req.Exclude)
+	}
+
+	if req = client.WithCompactMode(false).MakeRequest(41.8781, -87.6297); len(req.Exclude) != 0 {
+		t.Errorf("Expected nothing excluded without compact mode, got %v.", req.Exclude)
+	}
+}
+
+func TestClient_WithDisplayUnits(t *testing.T) {
+	usingTestServer(validForecastHandler, func(testURL string) {
+		client := NewClient(key).WithBaseURL(testURL).WithDisplayUnits(SI)
+
+		// The fixture is in US units, whatever was requested.
+		resp := client.MakeRequest(41.8781, -87.6297).WithUnits(AUTO).Get()
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if resp.Forecast.Flags.Units != "si" || !closeTo(resp.Forecast.Currently.Temperature, (37.57-32)*5/9) {
+			t.Errorf("Expected the forecast to be converted to SI, got %v in %v.", resp.Forecast.Currently.Temperature, resp.Forecast.Flags.Units)
+		}
+
+		// Fields the API left out must stay 0, so they're still seen as missing, as by
+		// ComputeApparentTemperature.
+		minutely, daily := resp.Forecast.Minutely.Data[0], resp.Forecast.Daily.Data[0]
+
+		if minutely.Temperature != 0 || minutely.ApparentTemperature != 0 || daily.Temperature != 0 || resp.Forecast.Hourly.Data[0].TemperatureHigh != 0 {
+			t.Errorf("Expected unconverted fields to stay 0, got %v, %v, %v and %v.", minutely.Temperature, minutely.ApparentTemperature, daily.Temperature, resp.Forecast.Hourly.Data[0].TemperatureHigh)
+		}
+
+		resp = client.WithDisplayUnits(US).MakeRequest(41.8781, -87.6297).WithUnits(AUTO).Get()
+		if resp.Forecast.Flags.Units != "us" || resp.Forecast.Currently.Temperature != 37.57 {
+			t.Errorf("Expected no conversion when the units match, got %v.", resp.Forecast.Currently.Temperature)
+		}
+
+		// Without flags, the request's units are assumed.
+		resp = client.WithDisplayUnits(SI).MakeRequest(41.8781, -87.6297).WithUnits(US).WithExclude([]string{"flags"}).Get()
+		if resp.Forecast.Flags == nil || resp.Forecast.Flags.Units != "si" {
+			t.Errorf("Expected the request's units to be converted from, got %+v.", resp.Forecast.Flags)
+		}
+	})
+}
+
+// redirectClient returns an http.Client that sends every request to testURL instead of its own host.
+func redirectClient(testURL string) *http.Client {
+	target, _ := url.Parse(testURL)
+
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
@@ -0,0 +1,208 @@
+package darksky
+
+// ConvertUnits returns a copy of the forecast with its values converted from the units system it
+// is in, read from Flags.Units (see ResolvedUnits), to the units system to. Flags.Units of the copy
+// is set to to. The blocks of the copy are independent of the original.
+//
+// The following fields of every DataPoint are converted:
+//
+//	Temperature, TemperatureMin, TemperatureMax,
+//	TemperatureHigh, TemperatureLow,
+//	ApparentTemperature, ApparentTemperatureMin,
+//	ApparentTemperatureMax, ApparentTemperatureHigh,
+//	ApparentTemperatureLow, DewPoint               °F or °C
+//	WindSpeed                                      mph, m/s or km/h
+//	Visibility                                     mi or km
+//	PrecipIntensity, PrecipIntensityMax            in/hr or mm/hr
+//	PrecipAccumulation                             in or cm
+//
+// Only temperatures the block carries are converted, so ones the API left out stay 0: Temperature
+// and ApparentTemperature on currently and hourly points, DewPoint on all but minutely points, and
+// the min, max, high and low temperatures only when their time field is set. Pressure is left
+// as-is, since millibars (US) and hectopascals (all others) are equal. If either units system is
+// unknown, including AUTO as the target, the copy is returned unconverted.
+func (f *Forecast) ConvertUnits(to Units) Forecast {
+	from := f.ResolvedUnits()
+	c := f.clone()
+
+	if from == to || from.TemperatureSymbol() == "" || to.TemperatureSymbol() == "" {
+		return c
+	}
+
+	if c.Currently != nil {
+		convertDataPoint(c.Currently, CurrentlyBlock, from, to)
+	}
+
+	blocks := []struct {
+		name DataBlockName
+		db   *DataBlock
+	}{{MinutelyBlock, c.Minutely}, {HourlyBlock, c.Hourly}, {DailyBlock, c.Daily}}
+
+	for _, b := range blocks {
+		if b.db == nil {
+			continue
+		}
+
+		for i := range b.db.Data {
+			convertDataPoint(&b.db.Data[i], b.name, from, to)
+		}
+	}
+
+	if c.Flags == nil {
+		c.Flags = &Flags{}
+	}
+
+	c.Flags.Units = string(to)
+
+	return c
+}
+
+// applyDisplayUnits converts the forecast in fr to the Client's display units, unless it's already
+// in them. The request's Units are used when the forecast has no flags to read its units from.
+func (f *ForecastRequest) applyDisplayUnits(fr *ForecastResponse) {
+	forecast := fr.Forecast
+
+	if forecast.Flags == nil || forecast.Flags.Units == "" {
+		if f.Units == AUTO {
+			return
+		}
+
+		forecast.Flags = &Flags{Units: string(f.Units)}
+	}
+
+	if forecast.ResolvedUnits() != f.client.display {
+		fr.Forecast = forecast.ConvertUnits(f.client.display)
+	}
+}
+
+// clone returns a copy of the forecast whose blocks, alerts and flags are independent of the original.
+func (f *Forecast) clone() Forecast {
+	c := *f
+
+	if f.Currently != nil {
+		dp := *f.Currently
+		c.Currently = &dp
+	}
+
+	c.Minutely = f.Minutely.clone()
+	c.Hourly = f.Hourly.clone()
+	c.Daily = f.Daily.clone()
+
+	if f.Alerts != nil {
+		c.Alerts = append([]Alert{}, f.Alerts...)
+	}
+
+	if f.Flags != nil {
+		flags := *f.Flags
+		c.Flags = &flags
+	}
+
+	return c
+}
+
+// clone returns a copy of the block with its own Data slice. Safe to call on a nil DataBlock.
+func (db *DataBlock) clone() *DataBlock {
+	if db == nil {
+		return nil
+	}
+
+	c := *db
+	c.Data = append([]DataPoint(nil), db.Data...)
+	return &c
+}
+
+// convertDataPoint converts the values of a data point from the given block in place. See
+// Forecast.ConvertUnits.
+func convertDataPoint(dp *DataPoint, block DataBlockName, from Units, to Units) {
+	var temperatures []*float64
+
+	if block == CurrentlyBlock || block == HourlyBlock {
+		temperatures = append(temperatures, &dp.Temperature, &dp.ApparentTemperature)
+	}
+
+	if block != MinutelyBlock {
+		temperatures = append(temperatures, &dp.DewPoint)
+	}
+
+	timed := []struct {
+		v *float64
+		t int64
+	}{
+		{&dp.TemperatureMin, dp.TemperatureMinTime},
+		{&dp.TemperatureMax, dp.TemperatureMaxTime},
+		{&dp.TemperatureHigh, dp.TemperatureHighTime},
+		{&dp.TemperatureLow, dp.TemperatureLowTime},
+		{&dp.ApparentTemperatureMin, dp.ApparentTemperatureMinTime},
+		{&dp.ApparentTemperatureMax, dp.ApparentTemperatureMaxTime},
+		{&dp.ApparentTemperatureHigh, dp.ApparentTemperatureHighTime},
+		{&dp.ApparentTemperatureLow, dp.ApparentTemperatureLowTime},
+	}
+
+	for _, field := range timed {
+		if field.t != 0 {
+			temperatures = append(temperatures, field.v)
+		}
+	}
+
+	for _, t := range temperatures {
+		*t = fromFahrenheit(toFahrenheit(*t, from), to)
+	}
+
+	dp.WindSpeed = fromMs(speedMs(dp.WindSpeed, from), to)
+	dp.Visibility = fromKm(toKm(dp.Visibility, from), to)
+	dp.PrecipIntensity = convertPrecip(dp.PrecipIntensity, from, to, 25.4)
+	dp.PrecipIntensityMax = convertPrecip(dp.PrecipIntensityMax, from, to, 25.4)
+	dp.PrecipAccumulation = convertPrecip(dp.PrecipAccumulation, from, to, 2.54)
+}
+
+// fromMs converts a speed in meters per second to the units system u. See speedMs.
+func fromMs(v float64, u Units) float64 {
+	return v / speedMs(1, u)
+}
+
+// toKm converts a distance in the units system u to kilometers. See distanceInMiles.
+func toKm(v float64, u Units) float64 {
+	if distanceInMiles(u) {
+		return v * metersPerMile / 1000
+	}
+
+	return v
+}
+
+// fromKm converts a distance in kilometers to the units system u.
+func fromKm(v float64, u Units) float64 {
+	return v / toKm(1, u)
+}
+
+// convertPrecip converts a precipitation value between units systems. Precipitation is in inches
+// for US and metric for every other units system, where metricPerInch is the number of metric
+// units in an inch: 25.4 for intensity in mm/hr, 2.54 for accumulation in cm.
+func convertPrecip(v float64, from Units, to Units, metricPerInch float64) float64 {
+	if from == US {
+		v *= metricPerInch
+	}
+
+	if to == US {
+		v /= metricPerInch
+	}
+
+	return v
+}
+
+// eachDataPointRef calls fn with a pointer to every DataPoint in the forecast, so they can be
+// modified in place.
+func (f *Forecast) eachDataPointRef(fn func(dp *DataPoint)) {
+	if f.Currently != nil {
+		fn(f.Currently)
+	}
+
+	for _, db := range []*DataBlock{f.Minutely, f.Hourly, f.Daily} {
+		if db == nil {
+			continue
+		}
+
+		for i := range db.Data {
+			fn(&db.Data[i])
+		}
+	}
+}
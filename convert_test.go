@@ -0,0 +1,89 @@
+package darksky
+
+import "testing"
+
+func TestForecast_ConvertUnits(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+	forecast.Currently.PrecipAccumulation = 1
+
+	si := forecast.ConvertUnits(SI)
+
+	if si.ResolvedUnits() != SI {
+		t.Errorf("Expected the copy to be in SI units, was %v.", si.ResolvedUnits())
+	}
+
+	if forecast.ResolvedUnits() != US || forecast.Currently.Temperature != 37.57 {
+		t.Error("Expected the original forecast to be unchanged.")
+	}
+
+	cur := si.Currently
+
+	checks := []struct {
+		name     string
+		value    float64
+		expected float64
+	}{
+		{"Temperature", cur.Temperature, (37.57 - 32) * 5 / 9},
+		{"ApparentTemperature", cur.ApparentTemperature, (32.2 - 32) * 5 / 9},
+		{"DewPoint", cur.DewPoint, (36.08 - 32) * 5 / 9},
+		{"WindSpeed", cur.WindSpeed, 7.02 * 0.44704},
+		{"Visibility", cur.Visibility, 2.76 * 1.609344},
+		{"PrecipIntensity", cur.PrecipIntensity, 0.0037 * 25.4},
+		{"PrecipAccumulation", cur.PrecipAccumulation, 2.54},
+		{"Pressure", cur.Pressure, 999.96},
+		{"Humidity", cur.Humidity, 0.94},
+		{"Daily TemperatureMax", si.Daily.Data[0].TemperatureMax, (forecast.Daily.Data[0].TemperatureMax - 32) * 5 / 9},
+	}
+
+	for _, check := range checks {
+		if !closeTo(check.value, check.expected) {
+			t.Errorf("Expected %v to be %v, was %v.", check.name, check.expected, check.value)
+		}
+	}
+
+	if &si.Hourly.Data[0] == &forecast.Hourly.Data[0] {
+		t.Error("Expected the copy to have its own data points.")
+	}
+
+	// Converting back should give the original values.
+	us := si.ConvertUnits(US)
+
+	if !closeTo(us.Currently.Temperature, 37.57) || !closeTo(us.Currently.WindSpeed, 7.02) || !closeTo(us.Currently.PrecipAccumulation, 1) {
+		t.Errorf("Expected a round trip to restore the original values, got %+v.", us.Currently)
+	}
+
+	ca := forecast.ConvertUnits(CA)
+
+	if !closeTo(ca.Currently.WindSpeed, 7.02*1.609344) || !closeTo(ca.Currently.Visibility, 2.76*1.609344) {
+		t.Errorf("Expected CA wind in km/h and visibility in km, got %v and %v.", ca.Currently.WindSpeed, ca.Currently.Visibility)
+	}
+
+	absent := []struct {
+		name  string
+		value float64
+	}{
+		{"Daily Temperature", si.Daily.Data[0].Temperature},
+		{"Daily ApparentTemperature", si.Daily.Data[0].ApparentTemperature},
+		{"Hourly TemperatureHigh", si.Hourly.Data[0].TemperatureHigh},
+		{"Hourly ApparentTemperatureMin", si.Hourly.Data[0].ApparentTemperatureMin},
+		{"Minutely Temperature", si.Minutely.Data[0].Temperature},
+		{"Minutely ApparentTemperature", si.Minutely.Data[0].ApparentTemperature},
+		{"Minutely DewPoint", si.Minutely.Data[0].DewPoint},
+	}
+
+	for _, field := range absent {
+		if field.value != 0 {
+			t.Errorf("Expected %v, which the API left out, to stay 0, was %v.", field.name, field.value)
+		}
+	}
+
+	if !closeTo(si.Daily.Data[0].TemperatureHigh, si.Daily.Data[0].TemperatureMax) {
+		t.Errorf("Expected the daily TemperatureHigh filled from TemperatureMax to be converted, was %v.", si.Daily.Data[0].TemperatureHigh)
+	}
+
+	auto := forecast.ConvertUnits(AUTO)
+
+	if auto.ResolvedUnits() != US || auto.Currently.Temperature != 37.57 {
+		t.Error("Expected converting to AUTO to leave the forecast unconverted.")
+	}
+}
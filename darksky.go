@@ -12,57 +12,310 @@ Requires an API Key to use. To register go to:
 package darksky
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Forecast is the top level representation of the weather forecast for a location.
+// Blocks that were excluded from the request, or omitted by the API, are nil.
+//
+// Extra holds any top level fields in the response that Forecast doesn't model, such as ones added
+// to the API after this package was released, and DataPoint.Extra does the same for each data point.
+// They're only collected for requests made WithExtraFields, or when parsed with
+// ParseForecastWithExtra or UnmarshalWithExtra, and are nil otherwise or if there were none.
 type Forecast struct {
-	Latitude  float64   `json:"latitude"`
-	Longitude float64   `json:"longitude"`
-	Timezone  string    `json:"timezone"`
-	Offset    int       `json:"offset"`
-	Currently DataPoint `json:"currently,omitempty"`
-	Minutely  DataBlock `json:"minutely,omitempty"`
-	Hourly    DataBlock `json:"hourly,omitempty"`
-	Daily     DataBlock `json:"daily,omitempty"`
-	Alerts    []Alert   `json:"alerts,omitempty"`
-	Flags     Flags     `json:"flags,omitempty"`
-}
-
-// DataPoint is the current weather data for a single point in time.
+	Latitude  float64    `json:"latitude"`
+	Longitude float64    `json:"longitude"`
+	Timezone  string     `json:"timezone"`
+	Offset    int        `json:"offset"`
+	Currently *DataPoint `json:"currently,omitempty"`
+	Minutely  *DataBlock `json:"minutely,omitempty"`
+	Hourly    *DataBlock `json:"hourly,omitempty"`
+	Daily     *DataBlock `json:"daily,omitempty"`
+	Alerts    []Alert    `json:"alerts,omitempty"`
+	Flags     *Flags     `json:"flags,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// HasCurrently reports whether the forecast contains the currently data point. Safe to call on a nil Forecast.
+func (f *Forecast) HasCurrently() bool {
+	return f != nil && f.Currently != nil
+}
+
+// HasMinutely reports whether the forecast contains the minutely block. Safe to call on a nil Forecast.
+func (f *Forecast) HasMinutely() bool {
+	return f != nil && f.Minutely != nil
+}
+
+// HasHourly reports whether the forecast contains the hourly block. Safe to call on a nil Forecast.
+func (f *Forecast) HasHourly() bool {
+	return f != nil && f.Hourly != nil
+}
+
+// HasDaily reports whether the forecast contains the daily block. Safe to call on a nil Forecast.
+func (f *Forecast) HasDaily() bool {
+	return f != nil && f.Daily != nil
+}
+
+// EachDataPoint calls fn for every DataPoint in the forecast, in the order currently, minutely,
+// hourly and daily, along with the JSON name of the block it came from. Missing blocks are skipped.
+func (f *Forecast) EachDataPoint(fn func(block string, dp DataPoint)) {
+	if f == nil {
+		return
+	}
+
+	if f.Currently != nil {
+		fn("currently", *f.Currently)
+	}
+
+	blocks := []struct {
+		name  string
+		block *DataBlock
+	}{
+		{"minutely", f.Minutely},
+		{"hourly", f.Hourly},
+		{"daily", f.Daily},
+	}
+
+	for _, b := range blocks {
+		if b.block == nil {
+			continue
+		}
+
+		for _, dp := range b.block.Data {
+			fn(b.name, dp)
+		}
+	}
+}
+
+// IsDegraded reports whether the forecast was produced without the Dark Sky data source, and may
+// be less accurate than usual. The reason is available from DegradedReason.
+func (f *Forecast) IsDegraded() bool {
+	return f != nil && f.Flags.IsUnavailable()
+}
+
+// DegradedReason returns the API's explanation of why the forecast is degraded, or an empty string
+// if it isn't.
+func (f *Forecast) DegradedReason() string {
+	if !f.IsDegraded() {
+		return ""
+	}
+
+	return f.Flags.DarkSkyUnavailable
+}
+
+// LocalTime converts seconds since epoch to a time.Time in a fixed zone built from Offset.
+// Unlike the IANA Timezone, this doesn't depend on the tz database, so it gives correct
+// wall clock times in restricted environments where time.LoadLocation fails.
+func (f *Forecast) LocalTime(unix int64) time.Time {
+	return time.Unix(unix, 0).In(f.fixedZone())
+}
+
+// Location returns the forecast's IANA Timezone as a *time.Location. Loaded locations are cached
+// for the life of the process, so repeated calls are cheap. If the timezone is missing or can't be
+// loaded, such as when the tz database isn't installed, a fixed zone built from Offset is returned
+// along with the error. The fixed zone gives correct times for the forecast period, but not across
+// daylight saving changes.
+func (f *Forecast) Location() (*time.Location, error) {
+	if f.Timezone == "" {
+		return f.fixedZone(), errors.New(TimezoneMissing)
+	}
+
+	cached, ok := locations.Load(f.Timezone)
+	if !ok {
+		loc, err := time.LoadLocation(f.Timezone)
+		cached, _ = locations.LoadOrStore(f.Timezone, loadedLocation{loc, err})
+	}
+
+	l := cached.(loadedLocation)
+	if l.err != nil {
+		return f.fixedZone(), l.err
+	}
+
+	return l.loc, nil
+}
+
+// TimezoneMissing is the error returned by Location when the forecast has no Timezone.
+const TimezoneMissing = "forecast has no timezone"
+
+// loadedLocation is the result of loading a timezone, cached in locations.
+type loadedLocation struct {
+	loc *time.Location
+	err error
+}
+
+// locations caches the result of time.LoadLocation by timezone name.
+var locations sync.Map
+
+// location returns the forecast's timezone, ignoring the error from Location.
+func (f *Forecast) location() *time.Location {
+	loc, _ := f.Location()
+	return loc
+}
+
+// IssuedAt returns the time the forecast is as of, in the forecast's timezone. This is the time of
+// the currently data point, or when currently was excluded, the earliest time of the first data
+// point in the minutely, hourly and daily blocks. The zero time is returned if none of these are
+// present. Safe to call on a nil Forecast.
+func (f *Forecast) IssuedAt() time.Time {
+	if f == nil {
+		return time.Time{}
+	}
+
+	if f.Currently != nil {
+		return time.Unix(f.Currently.Time, 0).In(f.location())
+	}
+
+	var earliest int64
+	var found bool
+
+	for _, db := range []*DataBlock{f.Minutely, f.Hourly, f.Daily} {
+		if db != nil && len(db.Data) > 0 && (!found || db.Data[0].Time < earliest) {
+			earliest, found = db.Data[0].Time, true
+		}
+	}
+
+	if !found {
+		return time.Time{}
+	}
+
+	return time.Unix(earliest, 0).In(f.location())
+}
+
+// Today returns the daily data point for the forecast's current day in its own timezone. The
+// current day is taken from the currently block, or Now when it's missing. The
+// found flag is false when the daily block has no point for that day.
+func (f *Forecast) Today() (DataPoint, bool) {
+	if f == nil {
+		return DataPoint{}, false
+	}
+
+	now := Now()
+	if f.Currently != nil {
+		now = time.Unix(f.Currently.Time, 0)
+	}
+
+	return f.dailyPoint(now)
+}
+
+// dailyPoint returns the daily data point for the day t falls on in the forecast's timezone.
+func (f *Forecast) dailyPoint(t time.Time) (DataPoint, bool) {
+	if !f.HasDaily() {
+		return DataPoint{}, false
+	}
+
+	loc := f.location()
+	year, month, day := t.In(loc).Date()
+
+	for _, dp := range f.Daily.Data {
+		if y, m, d := time.Unix(dp.Time, 0).In(loc).Date(); y == year && m == month && d == day {
+			return dp, true
+		}
+	}
+
+	return DataPoint{}, false
+}
+
+// DefaultRainThreshold is the PrecipProbability a minutely data point must exceed for
+// RainStartsWithin to consider precipitation likely.
+const DefaultRainThreshold = 0.5
+
+// RainStartsWithin is RainStartsWithinThreshold using DefaultRainThreshold.
+func (f *Forecast) RainStartsWithin(d time.Duration) (time.Time, bool) {
+	return f.RainStartsWithinThreshold(d, DefaultRainThreshold)
+}
+
+// RainStartsWithinThreshold returns the time of the first minutely data point within d of now whose
+// PrecipProbability is above threshold, in the forecast's timezone. Now is the time of the
+// currently block, or Now when it's missing. Precipitation of any type counts, see PrecipType. The
+// found flag is false when the minutely block is missing or no point in the window is above the
+// threshold.
+func (f *Forecast) RainStartsWithinThreshold(d time.Duration, threshold float64) (time.Time, bool) {
+	if !f.HasMinutely() {
+		return time.Time{}, false
+	}
+
+	now := Now()
+	if f.Currently != nil {
+		now = time.Unix(f.Currently.Time, 0)
+	}
+
+	start, end := now.Unix(), now.Add(d).Unix()
+
+	for _, dp := range f.Minutely.Data {
+		if dp.Time < start || dp.Time > end {
+			continue
+		}
+
+		if dp.PrecipProbability > threshold {
+			return time.Unix(dp.Time, 0).In(f.location()), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (f *Forecast) fixedZone() *time.Location {
+	return time.FixedZone(f.Timezone, f.Offset*3600)
+}
+
+// DataPoint is the current weather data for a single point in time. Fields that DataPoint doesn't
+// model can be kept in Extra, see Forecast. Because Extra is a map, DataPoint values can't be
+// compared with == or used as map keys.
 type DataPoint struct {
-	Time                   int64   `json:"time"`
-	Summary                string  `json:"summary"`
-	Icon                   string  `json:"icon"`
-	SunriseTime            int64   `json:"sunriseTime"`
-	SunsetTime             int64   `json:"sunsetTime"`
-	PrecipIntensity        float64 `json:"precipIntensity"`
-	PrecipIntensityMax     float64 `json:"precipIntensityMax"`
-	PrecipIntensityMaxTime int64   `json:"precipIntensityMaxTime"`
-	PrecipProbability      float64 `json:"precipProbability"`
-	PrecipType             string  `json:"precipType"`
-	PrecipAccumulation     float64 `json:"precipAccumulation"`
-	Temperature            float64 `json:"temperature"`
-	TemperatureMin         float64 `json:"temperatureMin"`
-	TemperatureMinTime     int64   `json:"temperatureMinTime"`
-	TemperatureMax         float64 `json:"temperatureMax"`
-	TemperatureMaxTime     int64   `json:"temperatureMaxTime"`
-	ApparentTemperature    float64 `json:"apparentTemperature"`
-	DewPoint               float64 `json:"dewPoint"`
-	WindSpeed              float64 `json:"windSpeed"`
-	WindBearing            float64 `json:"windBearing"`
-	CloudCover             float64 `json:"cloudCover"`
-	Humidity               float64 `json:"humidity"`
-	Pressure               float64 `json:"pressure"`
-	Visibility             float64 `json:"visibility"`
-	Ozone                  float64 `json:"ozone"`
-	MoonPhase              float64 `json:"moonPhase"`
+	Time                        int64   `json:"time"`
+	Summary                     string  `json:"summary"`
+	Icon                        string  `json:"icon"`
+	SunriseTime                 int64   `json:"sunriseTime"`
+	SunsetTime                  int64   `json:"sunsetTime"`
+	PrecipIntensity             float64 `json:"precipIntensity"`
+	PrecipIntensityMax          float64 `json:"precipIntensityMax"`
+	PrecipIntensityMaxTime      int64   `json:"precipIntensityMaxTime"`
+	PrecipProbability           float64 `json:"precipProbability"`
+	PrecipType                  string  `json:"precipType"`
+	PrecipAccumulation          float64 `json:"precipAccumulation"`
+	Temperature                 float64 `json:"temperature"`
+	TemperatureMin              float64 `json:"temperatureMin"`
+	TemperatureMinTime          int64   `json:"temperatureMinTime"`
+	TemperatureMax              float64 `json:"temperatureMax"`
+	TemperatureMaxTime          int64   `json:"temperatureMaxTime"`
+	TemperatureHigh             float64 `json:"temperatureHigh"`
+	TemperatureHighTime         int64   `json:"temperatureHighTime"`
+	TemperatureLow              float64 `json:"temperatureLow"`
+	TemperatureLowTime          int64   `json:"temperatureLowTime"`
+	ApparentTemperature         float64 `json:"apparentTemperature"`
+	ApparentTemperatureMin      float64 `json:"apparentTemperatureMin"`
+	ApparentTemperatureMinTime  int64   `json:"apparentTemperatureMinTime"`
+	ApparentTemperatureMax      float64 `json:"apparentTemperatureMax"`
+	ApparentTemperatureMaxTime  int64   `json:"apparentTemperatureMaxTime"`
+	ApparentTemperatureHigh     float64 `json:"apparentTemperatureHigh"`
+	ApparentTemperatureHighTime int64   `json:"apparentTemperatureHighTime"`
+	ApparentTemperatureLow      float64 `json:"apparentTemperatureLow"`
+	ApparentTemperatureLowTime  int64   `json:"apparentTemperatureLowTime"`
+	DewPoint                    float64 `json:"dewPoint"`
+	WindSpeed                   float64 `json:"windSpeed"`
+	WindBearing                 float64 `json:"windBearing"`
+	CloudCover                  float64 `json:"cloudCover"`
+	Humidity                    float64 `json:"humidity"`
+	Pressure                    float64 `json:"pressure"`
+	Visibility                  float64 `json:"visibility"`
+	Ozone                       float64 `json:"ozone"`
+	MoonPhase                   float64 `json:"moonPhase"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // WindDirection converts the numerical WindBearing value in degrees to directional text. (ex: 200 => "SW")
@@ -95,12 +348,16 @@ type DataBlock struct {
 	Data    []DataPoint `json:"data"`
 }
 
-// Alert is a potentially serious weather condition.
+// Alert is a potentially serious weather condition. Time is when the alert was issued, and Regions
+// the names of the areas it covers.
 type Alert struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Expires     int64  `json:"expires"`
-	URI         string `json:"uri"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Time        int64    `json:"time"`
+	Expires     int64    `json:"expires"`
+	Severity    Severity `json:"severity"`
+	Regions     []string `json:"regions"`
+	URI         string   `json:"uri"`
 }
 
 // Flags contains meta data about the Forecast.
@@ -108,17 +365,54 @@ type Flags struct {
 	DarkSkyUnavailable string   `json:"darksky-unavailable"`
 	DarkSkyStations    []string `json:"darksky-stations"`
 	DataPointStations  []string `json:"datapoint-stations"`
-	ISDStations        []string `json:"isds-stations"`
+	ISDStations        []string `json:"isd-stations"`
 	LAMPStations       []string `json:"lamp-stations"`
+	MADISStations      []string `json:"madis-stations"`
 	METARStations      []string `json:"metars-stations"`
 	METNOLicense       string   `json:"metnol-license"`
 	Sources            []string `json:"sources"`
 	Units              string   `json:"units"`
 }
 
+// AllStations returns the stations from every source station list, in field order. A station
+// reported by more than one source appears once per source. Safe to call on nil Flags.
+func (fl *Flags) AllStations() []string {
+	if fl == nil {
+		return nil
+	}
+
+	var stations []string
+
+	for _, list := range [][]string{fl.DarkSkyStations, fl.DataPointStations, fl.ISDStations, fl.LAMPStations, fl.MADISStations, fl.METARStations} {
+		stations = append(stations, list...)
+	}
+
+	return stations
+}
+
+// IsUnavailable reports whether the Dark Sky data source was unavailable for the forecast, in
+// which case DarkSkyUnavailable explains why. Safe to call on nil Flags.
+func (fl *Flags) IsUnavailable() bool {
+	return fl != nil && fl.DarkSkyUnavailable != ""
+}
+
+// Now returns the current time for every helper in the package that depends on it: Forecast.Today,
+// the daily limit of a Client, MemoryCache expiry and the fallback for a missing Date header in
+// ForecastResponse.ValidUntil. It defaults to time.Now, and can be replaced to freeze time in
+// tests. Overriding it affects all of these at once, and it shouldn't be changed while requests are
+// in flight.
+var Now = time.Now
+
+// CurrentForecast is the ForecastRequest Time used to request the current forecast, rather than a
+// Time Machine request for a specific time.
+const CurrentForecast int64 = -1
+
 // ForecastRequest is the data needed to retrieve a forecast from the Dark Sky API.
 // Key, Lat, and Lng are required to make a basic request. All other fields are optional,
 // and have sensible defaults if created using MakeRequest.
+//
+// Time is seconds since unix epoch, or CurrentForecast for the current forecast. Any other
+// value, including 0, results in a Time Machine request.
 type ForecastRequest struct {
 	Key          string
 	Lat          float64
@@ -129,14 +423,63 @@ type ForecastRequest struct {
 	ExtendHourly bool
 	Exclude      []string
 	baseURL      string
+	header       http.Header
+	client       *Client
+	dryRun       bool
+	skipCache    bool
+	strict       bool
+	extra        bool
+	langFallback Lang
+	query        url.Values
+	correlation  string
+	err          error
 }
 
 // ForecastResponse is a wrapper struct for a response from the DarkSky API.
 // Errors are included to make it easier to pass single values via channel from a goroutine.
+// Duration is the wall time spent on the HTTP call, including reading the response, and is set
+// even when the call fails. ValidUntil is when the API suggests the forecast should be refreshed,
+// taken from the Cache-Control or Expires response headers, and is zero if neither was sent.
+// URL is only set for dry run requests, see WithDryRun. Request is the request the response is for,
+// so responses can be matched up when they arrive out of order, as from GetAllStream.
+// CallCountKnown is true only when APICallCount was parsed from the response, distinguishing a
+// missing header from a count of 0.
+// Failures of the call itself are reported as a *NetworkError, *DecodeError or *APIError, which
+// can be told apart with errors.As. StatusCode is the HTTP status of the response, such as 203 when
+// served by a caching proxy or 304 for a conditional request, and is 0 when no HTTP response was
+// received, including forecasts served from a Client's Cache.
 type ForecastResponse struct {
-	Forecast     Forecast
-	APICallCount int
-	Error        error
+	Forecast       Forecast
+	APICallCount   int
+	CallCountKnown bool
+	StatusCode     int
+	Duration       time.Duration
+	ValidUntil     time.Time
+	URL            string
+	Request        *ForecastRequest
+	Error          error
+}
+
+// TemperatureLabel returns the symbol for the temperature unit the forecast is in, "°F" or "°C",
+// using the units resolved by the API. This is the unit actually used, even for AUTO requests.
+func (fr ForecastResponse) TemperatureLabel() string {
+	return fr.Forecast.ResolvedUnits().TemperatureSymbol()
+}
+
+// RemainingCalls returns how many API calls are left today, given the daily limit for the key
+// (1000 on the free tier), based on APICallCount. Zero is returned once the limit is reached. The
+// bool is CallCountKnown, and is false when the response had no call count, such as a cache hit
+// or a failed call, in which case 0 is returned.
+func (fr ForecastResponse) RemainingCalls(dailyLimit int) (int, bool) {
+	if !fr.CallCountKnown {
+		return 0, false
+	}
+
+	if fr.APICallCount >= dailyLimit {
+		return 0, true
+	}
+
+	return dailyLimit - fr.APICallCount, true
 }
 
 // MakeRequest creates a new ForecastRequest with defaults for the optional fields. If
@@ -147,7 +490,7 @@ func MakeRequest(key string, latitude float64, longitude float64) *ForecastReque
 		Key:          key,
 		Lat:          latitude,
 		Lng:          longitude,
-		Time:         -1,
+		Time:         CurrentForecast,
 		Lang:         English,
 		Units:        US,
 		ExtendHourly: false,
@@ -156,22 +499,73 @@ func MakeRequest(key string, latitude float64, longitude float64) *ForecastReque
 	}
 }
 
-// Get makes an outbound call to the Dark Sky API, using the provided fields in the ForecastRequest.
-func (f *ForecastRequest) Get() ForecastResponse {
+// Validate checks the request without making a network call, returning an error listing every
+// problem found, joined with errors.Join, or nil if there are none. The key must be set, the
+// latitude and longitude within bounds, Units must be a known value, and Lang a well formed language
+// code. Lang isn't limited to the Lang constants, so languages the API supports that this package
+// has no constant for, such as "ja", can still be requested. Any error from an earlier builder
+// method, such as WithLocalTime with an unknown timezone, is included too. Get validates each
+// request before calling the API.
+func (f *ForecastRequest) Validate() error {
+	var errs []error
 
 	if len(f.Key) == 0 {
-		return ForecastResponse{Error: errors.New(KeyRequired)}
+		errs = append(errs, errors.New(KeyRequired))
 	}
 
 	if f.Lat < -90.0 || f.Lat > 90.0 {
-		return ForecastResponse{Error: errors.New(LatitudeInvalid)}
+		errs = append(errs, errors.New(LatitudeInvalid))
 	}
 
 	if f.Lng < -180.0 || f.Lng > 180.0 {
-		return ForecastResponse{Error: errors.New(LongitudeInvalid)}
+		errs = append(errs, errors.New(LongitudeInvalid))
 	}
 
-	fr := ForecastResponse{}
+	if err := new(Units).UnmarshalText([]byte(f.Units)); err != nil {
+		errs = append(errs, errors.New(UnitsInvalid))
+	}
+
+	if !langCode.MatchString(string(f.Lang)) {
+		errs = append(errs, errors.New(LangInvalid))
+	}
+
+	if f.err != nil {
+		errs = append(errs, f.err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// langCode matches the language codes the API accepts, such as "en", "zh-tw" and "x-pig-latin".
+var langCode = regexp.MustCompile(`^([a-z]{2,3}|x)(-[a-z]{1,8})*$`)
+
+// Get makes an outbound call to the Dark Sky API, using the provided fields in the ForecastRequest.
+func (f *ForecastRequest) Get() ForecastResponse {
+	return f.GetWithContext(context.Background())
+}
+
+// GetWithContext is Get, with the HTTP call bound to ctx. If ctx is done before the response is
+// read, the returned error wraps ctx.Err().
+func (f *ForecastRequest) GetWithContext(ctx context.Context) ForecastResponse {
+	fr := f.get(ctx)
+
+	if fr.Error == nil && f.langFallback != "" && f.langFallback != f.Lang && hasBlankSummary(&fr.Forecast) {
+		f.applyLangFallback(ctx, &fr)
+	}
+
+	if fr.Error == nil && !f.dryRun && f.client != nil && f.client.display != "" {
+		f.applyDisplayUnits(&fr)
+	}
+
+	fr.Request = f
+	return fr
+}
+
+// get makes a single call to the API for GetWithContext.
+func (f *ForecastRequest) get(ctx context.Context) (fr ForecastResponse) {
+	if err := f.Validate(); err != nil {
+		return ForecastResponse{Error: err}
+	}
 
 	reqURL, err := f.URL()
 	if err != nil {
@@ -179,42 +573,201 @@ func (f *ForecastRequest) Get() ForecastResponse {
 		return fr
 	}
 
-	res, err := http.Get(reqURL)
-	if err != nil {
-		fr.Error = err
+	if f.dryRun {
+		fr.URL = reqURL
 		return fr
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
-	defer res.Body.Close()
+	if f.client != nil && f.client.cache != nil && !f.skipCache {
+		if forecast, ok := f.client.cache.Get(reqURL); ok {
+			fr.Forecast = *forecast
+			return fr
+		}
+	}
+
+	if f.client != nil {
+		if err := f.client.reserve(); err != nil {
+			fr.Error = err
+			return fr
+		}
+
+		defer func() {
+			f.client.release(fr.APICallCount, fr.StatusCode != 0)
+		}()
+	}
+
+	start := time.Now()
+	defer func() {
+		fr.Duration = time.Since(start)
+		f.client.log(f, reqURL, fr)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		fr.Error = err
 		return fr
 	}
 
-	if res.StatusCode >= 400 {
-		fr.Error = errors.New(string(body))
+	for k, values := range f.header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	var cached ConditionalEntry
+	var isCached bool
+
+	if f.client != nil && f.client.conditional != nil {
+		cached, isCached = f.client.conditional.Get(reqURL)
+	}
+
+	if isCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	res, err := f.client.client().Do(req)
+	if err != nil {
+		fr.Error = &NetworkError{Err: err}
 		return fr
 	}
 
+	defer res.Body.Close()
+
+	fr.StatusCode = res.StatusCode
+
 	callCount, err := strconv.Atoi(res.Header.Get(APICallsHeader))
 	if err == nil {
 		fr.APICallCount = callCount
+		fr.CallCountKnown = true
+	}
+
+	if res.StatusCode >= 400 {
+		body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodySize+1))
+		if err != nil {
+			fr.Error = &NetworkError{Err: err}
+			return fr
+		}
+
+		if len(body) > maxErrorBodySize {
+			body = append(body[:maxErrorBodySize], "..."...)
+		}
+
+		fr.Error = newAPIError(res.StatusCode, res.Header.Get("Content-Type"), body)
+		return fr
+	}
+
+	fr.ValidUntil = validUntil(res.Header)
+
+	if res.StatusCode == http.StatusNotModified && isCached {
+		fr.Forecast = cached.Forecast
+		return fr
 	}
 
-	forecast, err := fromJSON(body)
+	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		fr.Error = err
+		fr.Error = &NetworkError{Err: err}
+		return fr
+	}
+
+	if !isJSON(res.Header.Get("Content-Type"), body) {
+		fr.Error = &DecodeError{Err: nonJSONError(body)}
+		return fr
+	}
+
+	forecast, err := decodeForecast(body, f.strict, f.extra)
+	if err != nil {
+		fr.Error = &DecodeError{Err: err}
 		return fr
 	}
 
 	fr.Forecast = *forecast
 
+	if f.client != nil && f.client.cache != nil {
+		f.client.cache.Set(reqURL, forecast)
+	}
+
+	if f.client != nil && f.client.conditional != nil {
+		etag, lastModified := res.Header.Get("ETag"), res.Header.Get("Last-Modified")
+
+		if etag != "" || lastModified != "" {
+			f.client.conditional.Set(reqURL, ConditionalEntry{Forecast: fr.Forecast, ETag: etag, LastModified: lastModified})
+		}
+	}
+
 	return fr
 }
 
+// maxErrorBodySize is the most of an error response body read into its error message.
+const maxErrorBodySize = 8 * 1024
+
+// maxSnippetSize is the most of a non-JSON response body included in its error.
+const maxSnippetSize = 256
+
+// isJSON reports whether a response looks like JSON, based on its content type and the first
+// non-whitespace byte of its body.
+func isJSON(contentType string, body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return !strings.Contains(contentType, "html") && len(body) > 0 && body[0] == '{'
+}
+
+// isHTML reports whether a response looks like an HTML page, such as a proxy's outage page.
+func isHTML(contentType string, body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return strings.Contains(contentType, "html") || (len(body) > 0 && body[0] == '<')
+}
+
+// nonJSONError describes an unexpected non-JSON response, including a truncated snippet of the body.
+func nonJSONError(body []byte) error {
+	snippet := string(bytes.TrimSpace(body))
+	if len(snippet) > maxSnippetSize {
+		snippet = snippet[:maxSnippetSize] + "..."
+	}
+
+	return fmt.Errorf("%v: %q", NonJSONResponse, snippet)
+}
+
+// validUntil returns the expiry time given by the Cache-Control max-age directive, relative to the
+// Date header, or failing that the Expires header. The zero time is returned if neither is present.
+func validUntil(h http.Header) time.Time {
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil {
+			break
+		}
+
+		date, err := http.ParseTime(h.Get("Date"))
+		if err != nil {
+			date = Now()
+		}
+
+		return date.Add(time.Duration(secs) * time.Second)
+	}
+
+	if expires, err := http.ParseTime(h.Get("Expires")); err == nil {
+		return expires
+	}
+
+	return time.Time{}
+}
+
 // URL constructs and returns the valid url to request a forecast from the Dark Sky API.
 func (f *ForecastRequest) URL() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+
 	reqURL, err := url.Parse(f.baseURL)
 
 	if err != nil {
@@ -225,9 +778,23 @@ func (f *ForecastRequest) URL() (string, error) {
 	v.Add("lang", string(f.Lang))
 	v.Add("units", string(f.Units))
 
+	if len(f.Exclude) > 0 {
+		v.Add("exclude", strings.Join(f.Exclude, ","))
+	}
+
+	if f.ExtendHourly {
+		v.Add("extend", "hourly")
+	}
+
+	for k, values := range f.query {
+		if !isKnownParam(k) {
+			v[k] = append([]string{}, values...)
+		}
+	}
+
 	reqURL.Path = fmt.Sprintf("%v/%v/%v,%v", reqURL.Path, f.Key, f.Lat, f.Lng)
 
-	if f.Time > 0 {
+	if f.Time != CurrentForecast {
 		reqURL.Path = reqURL.Path + "," + strconv.FormatInt(f.Time, 10)
 	}
 
@@ -236,6 +803,89 @@ func (f *ForecastRequest) URL() (string, error) {
 	return reqURL.String(), nil
 }
 
+// knownParams are the query parameters set from ForecastRequest fields by URL.
+var knownParams = []string{"lang", "units", "exclude", "extend"}
+
+func isKnownParam(key string) bool {
+	for _, p := range knownParams {
+		if p == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseRequestURL reconstructs a ForecastRequest from a URL produced by URL, which is useful when
+// replaying logged requests. The key, latitude, longitude, optional time, and the lang, units,
+// exclude and extend query parameters are extracted. Any other query parameters are kept as if
+// set with WithQueryParam. Parameters missing from the URL get the same defaults as MakeRequest.
+func ParseRequestURL(raw string) (*ForecastRequest, error) {
+	reqURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(reqURL.Path, "/")
+	if len(segments) < 3 || segments[len(segments)-2] == "" {
+		return nil, errors.New(RequestURLInvalid)
+	}
+
+	coords := strings.Split(segments[len(segments)-1], ",")
+	if len(coords) < 2 || len(coords) > 3 {
+		return nil, errors.New(RequestURLInvalid)
+	}
+
+	lat, err := strconv.ParseFloat(coords[0], 64)
+	if err != nil {
+		return nil, errors.New(RequestURLInvalid)
+	}
+
+	lng, err := strconv.ParseFloat(coords[1], 64)
+	if err != nil {
+		return nil, errors.New(RequestURLInvalid)
+	}
+
+	baseURL := url.URL{Scheme: reqURL.Scheme, Host: reqURL.Host, Path: strings.Join(segments[:len(segments)-2], "/")}
+
+	f := MakeRequest(segments[len(segments)-2], lat, lng).WithBaseURL(baseURL.String())
+
+	if len(coords) == 3 {
+		t, err := strconv.ParseInt(coords[2], 10, 64)
+		if err != nil {
+			return nil, errors.New(RequestURLInvalid)
+		}
+
+		f.Time = t
+	}
+
+	v := reqURL.Query()
+
+	if lang := v.Get("lang"); lang != "" {
+		f.Lang = Lang(lang)
+	}
+
+	if units := v.Get("units"); units != "" {
+		f.Units = Units(units)
+	}
+
+	if exclude := v.Get("exclude"); exclude != "" {
+		f.Exclude = strings.Split(exclude, ",")
+	}
+
+	f.ExtendHourly = v.Get("extend") == "hourly"
+
+	for k, values := range v {
+		for _, value := range values {
+			if !isKnownParam(k) {
+				f.WithQueryParam(k, value)
+			}
+		}
+	}
+
+	return f, nil
+}
+
 // WithBaseURL will cause a request to be made to the provided baseURL. The expected format is
 // scheme://host:port/path. Useful for testing or hitting an internal proxy server.
 func (f *ForecastRequest) WithBaseURL(baseURL string) *ForecastRequest {
@@ -243,13 +893,178 @@ func (f *ForecastRequest) WithBaseURL(baseURL string) *ForecastRequest {
 	return f
 }
 
+// WithLocation sets the latitude and longitude of the forecast together, avoiding swapped arguments
+// when a coordinate pair comes from a geocoder.
+func (f *ForecastRequest) WithLocation(lat float64, lng float64) *ForecastRequest {
+	f.Lat = lat
+	f.Lng = lng
+	return f
+}
+
+// Clone returns an independent copy of the request, so a template request can be shared and
+// customized per location from multiple goroutines. The builder methods still modify their
+// receiver in place, so call them on the clone rather than the template.
+func (f *ForecastRequest) Clone() *ForecastRequest {
+	c := *f
+
+	if f.Exclude != nil {
+		c.Exclude = append([]string{}, f.Exclude...)
+	}
+
+	if f.header != nil {
+		c.header = f.header.Clone()
+	}
+
+	if f.query != nil {
+		c.query = url.Values{}
+
+		for k, values := range f.query {
+			c.query[k] = append([]string{}, values...)
+		}
+	}
+
+	return &c
+}
+
+// WithExclude leaves the given blocks out of the response, e.g. "minutely" or "flags". The slice
+// is copied, so later changes to it by the caller don't affect the request.
+func (f *ForecastRequest) WithExclude(exclude []string) *ForecastRequest {
+	f.Exclude = append([]string{}, exclude...)
+	return f
+}
+
+// WithOnly requests only the given blocks, by setting Exclude to every other block.
+func (f *ForecastRequest) WithOnly(blocks ...DataBlockName) *ForecastRequest {
+	f.Exclude = []string{}
+
+	for _, name := range dataBlockNames {
+		wanted := false
+		for _, b := range blocks {
+			wanted = wanted || b == name
+		}
+
+		if !wanted {
+			f.Exclude = append(f.Exclude, string(name))
+		}
+	}
+
+	return f
+}
+
+// WithAllBlocks resets the request to retrieve every block, clearing Exclude and ExtendHourly.
+// Useful when reusing a request that was previously narrowed down.
+func (f *ForecastRequest) WithAllBlocks() *ForecastRequest {
+	f.Exclude = []string{}
+	f.ExtendHourly = false
+	return f
+}
+
+// WithDryRun causes Get to return without calling the API, with an empty Forecast and the URL that
+// would have been requested in ForecastResponse.URL. The request is still validated. Useful during
+// development to avoid spending API calls.
+func (f *ForecastRequest) WithDryRun(dryRun bool) *ForecastRequest {
+	f.dryRun = dryRun
+	return f
+}
+
+// WithQueryParam adds an extra query parameter to the request URL, to use API features this package
+// doesn't support yet. Values accumulate, so repeating a key sends it multiple times. The lang,
+// units, exclude and extend parameters are always taken from the request's fields; setting them
+// here has no effect.
+func (f *ForecastRequest) WithQueryParam(key string, value string) *ForecastRequest {
+	if f.query == nil {
+		f.query = url.Values{}
+	}
+
+	f.query.Add(key, value)
+	return f
+}
+
+// WithStrictDecoding makes Get return a DecodeError when the response contains fields that aren't
+// modelled by this package, anywhere in the forecast, to catch schema changes in the API during
+// development. Decoding is lenient by default, ignoring unknown fields, which is what should be used
+// in production.
+func (f *ForecastRequest) WithStrictDecoding(strict bool) *ForecastRequest {
+	f.strict = strict
+	return f
+}
+
+// WithExtraFields keeps fields of the response that Forecast and DataPoint don't model in their
+// Extra maps. It's off by default because collecting them decodes the response a second time into
+// maps, which makes decoding several times slower and allocates around four times the memory, see
+// BenchmarkDecodeForecast_Extra.
+func (f *ForecastRequest) WithExtraFields(keep bool) *ForecastRequest {
+	f.extra = keep
+	return f
+}
+
+// WithHeader adds a header to the outbound HTTP request, such as a tracing header for a proxy.
+// Calling WithHeader again with the same key appends another value rather than replacing it,
+// following http.Header.Add.
+func (f *ForecastRequest) WithHeader(key string, value string) *ForecastRequest {
+	if f.header == nil {
+		f.header = http.Header{}
+	}
+
+	f.header.Add(key, value)
+	return f
+}
+
+// WithAcceptLanguage sets the Accept-Language header of the outbound HTTP request, replacing any
+// previous value, for proxies that vary their caching on it. It is independent of Lang, which
+// still controls the language of the summary text; the API itself ignores Accept-Language.
+func (f *ForecastRequest) WithAcceptLanguage(value string) *ForecastRequest {
+	if f.header == nil {
+		f.header = http.Header{}
+	}
+
+	f.header.Set("Accept-Language", value)
+	return f
+}
+
+// WithCorrelationID tags the request with an ID from the caller's own tracing, such as the ID of the
+// incoming request being served. It is sent in the X-Correlation-ID header and included in the
+// RequestLog passed to a Client's logger, see Client.WithLogger. An empty ID sends no header.
+func (f *ForecastRequest) WithCorrelationID(id string) *ForecastRequest {
+	f.correlation = id
+
+	if f.header != nil {
+		f.header.Del(CorrelationIDHeader)
+	}
+
+	if id != "" {
+		f.WithHeader(CorrelationIDHeader, id)
+	}
+
+	return f
+}
+
 // WithTime will cause a Forecast to be retrieved for the given time, specified as seconds
 // since unix epoch. This provides access to the "Time Machine" functionality of the Dark Sky API.
+// A time of 0 requests the forecast for the epoch itself, use CurrentForecast to go back to
+// requesting the current forecast. It replaces a time set by WithLocalTime, clearing the error from
+// an unknown timezone.
 func (f *ForecastRequest) WithTime(t int64) *ForecastRequest {
+	f.err = nil
 	f.Time = t
 	return f
 }
 
+// WithLocalTime is WithTime for a wall clock time at the forecast's location. The date and time of t
+// are interpreted in the IANA timezone tz, ignoring t's own location, and converted to seconds since
+// epoch. If tz can't be loaded the error is returned by URL and Get.
+func (f *ForecastRequest) WithLocalTime(t time.Time, tz string) *ForecastRequest {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		f.err = err
+		return f
+	}
+
+	f.err = nil
+	f.Time = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).Unix()
+	return f
+}
+
 // WithLang allows forecast text to be returned in the given language.
 func (f *ForecastRequest) WithLang(l Lang) *ForecastRequest {
 	f.Lang = l
@@ -267,9 +1082,37 @@ const (
 	KeyRequired      = "key is required"
 	LatitudeInvalid  = "latitude is not valid, must between -90 and +90 degrees"
 	LongitudeInvalid = "longitude is not valid, must between -180 and 180 degrees"
+	UnitsInvalid     = "units are not valid, must be one of the Units constants"
+	LangInvalid      = "lang is not valid, must be a language code such as \"en\" or \"zh-tw\""
+)
+
+// NonJSONResponse is the start of the error returned when the API responds with something other
+// than JSON, such as an HTML error page during an outage.
+const NonJSONResponse = "unexpected non-JSON response"
+
+// ParseRequestURL errors
+const (
+	RequestURLInvalid = "request url is not valid, path must end with /key/latitude,longitude[,time]"
 )
 
-// Units defines the possible options for measurement units used in the response.
+// DataBlockName names a block of the forecast response that can be excluded from a request.
+type DataBlockName string
+
+const (
+	CurrentlyBlock DataBlockName = "currently"
+	MinutelyBlock  DataBlockName = "minutely"
+	HourlyBlock    DataBlockName = "hourly"
+	DailyBlock     DataBlockName = "daily"
+	AlertsBlock    DataBlockName = "alerts"
+	FlagsBlock     DataBlockName = "flags"
+)
+
+// dataBlockNames is every DataBlockName, in the order the blocks appear in a response.
+var dataBlockNames = []DataBlockName{CurrentlyBlock, MinutelyBlock, HourlyBlock, DailyBlock, AlertsBlock, FlagsBlock}
+
+// Units defines the possible options for measurement units used in the response. AUTO is passed
+// through to the API as-is, which picks units based on the location; the units it chose are
+// reported by Forecast.ResolvedUnits.
 type Units string
 
 const (
@@ -311,14 +1154,66 @@ const (
 // APICallsHeader is the HTTP Header that contains the number of API calls made by the given key for the current 24 period.
 const APICallsHeader = "X-Forecast-API-Calls"
 
-func fromJSON(jsonBlob []byte) (*Forecast, error) {
-	var f Forecast
+// ParseForecast decodes a Forecast from a Dark Sky API response body. Combined with URL, it allows
+// the forecast to be fetched with any HTTP client or middleware and then parsed by this package.
+// Extra isn't filled in, use ParseForecastWithExtra for that.
+func ParseForecast(r io.Reader) (*Forecast, error) {
+	return parseForecast(r, false)
+}
 
-	err := json.Unmarshal(jsonBlob, &f)
+// ParseForecastWithExtra is ParseForecast, also keeping fields the forecast and its data points
+// don't model in their Extra maps, with the same cost as WithExtraFields.
+func ParseForecastWithExtra(r io.Reader) (*Forecast, error) {
+	return parseForecast(r, true)
+}
 
+func parseForecast(r io.Reader, extra bool) (*Forecast, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
+	return decodeForecast(data, false, extra)
+}
+
+// Unmarshal parses a Forecast from JSON, such as an archived API response. It behaves identically to
+// the parsing done by Get. Extra isn't filled in, use UnmarshalWithExtra for that.
+func Unmarshal(data []byte) (*Forecast, error) {
+	return decodeForecast(data, false, false)
+}
+
+// UnmarshalWithExtra is Unmarshal, also keeping fields the forecast and its data points don't model
+// in their Extra maps, the same as Get with WithExtraFields.
+func UnmarshalWithExtra(data []byte) (*Forecast, error) {
+	return decodeForecast(data, false, true)
+}
+
+// decodeForecast decodes a Forecast from data. When strict is true, unknown fields anywhere in the
+// forecast are an error. When extra is true, unknown fields of the forecast and its data points are
+// collected into Extra, see WithExtraFields.
+func decodeForecast(data []byte, strict bool, extra bool) (*Forecast, error) {
+	var f Forecast
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	if strict {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(&Forecast{}); err != nil {
+			return nil, err
+		}
+	}
+
+	if extra {
+		if err := collectExtra(&f, data); err != nil {
+			return nil, err
+		}
+	}
+
+	f.eachDataPointRef(normalizeTemperatures)
+
 	return &f, nil
 }
@@ -1,10 +1,15 @@
 package darksky
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestForecastRequest_Get(t *testing.T) {
@@ -16,10 +21,14 @@ func TestForecastRequest_Get(t *testing.T) {
 			return
 		}
 
-		if resp.APICallCount != 1 {
+		if resp.APICallCount != 1 || !resp.CallCountKnown {
 			t.Errorf("Expected APICallCount to be %v but was %v.", 1, resp.APICallCount)
 		}
 
+		if resp.Duration <= 0 {
+			t.Errorf("Expected Duration to be set, was %v.", resp.Duration)
+		}
+
 		forecast := resp.Forecast
 
 		if len(forecast.Alerts) != 3 {
@@ -28,6 +37,264 @@ func TestForecastRequest_Get(t *testing.T) {
 	})
 }
 
+func TestForecastRequest_Get_ValidUntil(t *testing.T) {
+	expires := time.Date(2015, 12, 29, 4, 30, 0, 0, time.UTC)
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Expires", expires.Format(http.TimeFormat))
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		if !resp.ValidUntil.Equal(expires) {
+			t.Errorf("Expected ValidUntil to be %v, was %v.", expires, resp.ValidUntil)
+		}
+	})
+
+	handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Date", expires.Format(http.TimeFormat))
+		resp.Header().Set("Cache-Control", "public, max-age=300")
+		resp.Header().Set("Expires", expires.Format(http.TimeFormat))
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		if !resp.ValidUntil.Equal(expires.Add(5 * time.Minute)) {
+			t.Errorf("Expected max-age to take precedence, ValidUntil was %v.", resp.ValidUntil)
+		}
+	})
+
+	usingTestServer(validForecastHandler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		if !resp.ValidUntil.IsZero() {
+			t.Errorf("Expected ValidUntil to be zero without cache headers, was %v.", resp.ValidUntil)
+		}
+	})
+}
+
+func TestForecastRequest_WithHeader(t *testing.T) {
+	var header http.Header
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).
+			WithBaseURL(testURL).
+			WithHeader("X-Trace-Id", "abc123").
+			WithHeader("X-Tag", "one").
+			WithHeader("X-Tag", "two").
+			Get()
+
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if header.Get("X-Trace-Id") != "abc123" {
+			t.Errorf("Expected X-Trace-Id to be sent, was %v.", header.Get("X-Trace-Id"))
+		}
+
+		if !reflect.DeepEqual(header["X-Tag"], []string{"one", "two"}) {
+			t.Errorf("Expected repeated headers to be appended, was %v.", header["X-Tag"])
+		}
+	})
+}
+
+func TestForecastRequest_WithStrictDecoding(t *testing.T) {
+	usingTestServer(validForecastHandler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+		if resp.Error != nil {
+			t.Fatalf("Expected lenient decoding by default, got %v.", resp.Error)
+		}
+
+		// The fixture includes nearestStormDistance, which Forecast doesn't model.
+		resp = MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithStrictDecoding(true).Get()
+		if _, ok := resp.Error.(*DecodeError); !ok || !strings.Contains(resp.Error.Error(), "unknown field") {
+			t.Errorf("Expected a DecodeError for an unknown field, got %v.", resp.Error)
+		}
+	})
+
+	for _, body := range []string{
+		`{"flags":{"units":"us","nearest-station":1.2}}`,
+		`{"alerts":[{"title":"Flood Watch","ends":1451362625}]}`,
+		`{"hourly":{"summary":"Rain","data":[],"source":"hrrr"}}`,
+	} {
+		if _, err := decodeForecast([]byte(body), false, false); err != nil {
+			t.Errorf("Expected %s to decode leniently, got %v.", body, err)
+		}
+
+		if _, err := decodeForecast([]byte(body), true, false); err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("Expected an unknown field error for %s, got %v.", body, err)
+		}
+	}
+}
+
+func TestForecastRequest_WithAcceptLanguage(t *testing.T) {
+	var header http.Header
+	var lang string
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		header, lang = req.Header, req.URL.Query().Get("lang")
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).
+			WithBaseURL(testURL).
+			WithLang(German).
+			WithAcceptLanguage("en-US").
+			WithAcceptLanguage("fr-CA").
+			Get()
+
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if !reflect.DeepEqual(header["Accept-Language"], []string{"fr-CA"}) {
+			t.Errorf("Expected only the last Accept-Language to be sent, was %v.", header["Accept-Language"])
+		}
+
+		if lang != "de" {
+			t.Errorf("Expected lang to be unaffected, was %v.", lang)
+		}
+	})
+}
+
+func TestForecastRequest_Get_StatusCode(t *testing.T) {
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		jsonBytes, _ := ioutil.ReadFile("testdata/chicago_forecast.json")
+		resp.WriteHeader(http.StatusNonAuthoritativeInfo)
+		resp.Write(jsonBytes)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+		if resp.Error != nil || resp.StatusCode != 203 {
+			t.Errorf("Expected a 203 status code, got %v, %v.", resp.StatusCode, resp.Error)
+		}
+	})
+
+	usingTestServer(errorForecastHandler, func(testURL string) {
+		if resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get(); resp.StatusCode != 500 {
+			t.Errorf("Expected a 500 status code, got %v.", resp.StatusCode)
+		}
+	})
+}
+
+func TestForecastRequest_Get_NoCallCount(t *testing.T) {
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		jsonBytes, _ := ioutil.ReadFile("testdata/chicago_forecast.json")
+		resp.Write(jsonBytes)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if resp.CallCountKnown || resp.APICallCount != 0 {
+			t.Errorf("Expected the call count to be unknown, was %v.", resp.APICallCount)
+		}
+	})
+}
+
+func TestForecastResponse_RemainingCalls(t *testing.T) {
+	tests := []struct {
+		calls     int
+		remaining int
+	}{
+		{0, 1000},
+		{1, 999},
+		{1000, 0},
+		{1200, 0},
+	}
+
+	for _, test := range tests {
+		resp := ForecastResponse{APICallCount: test.calls, CallCountKnown: true}
+
+		if remaining, ok := resp.RemainingCalls(1000); !ok || remaining != test.remaining {
+			t.Errorf("Expected %v calls to leave %v remaining, was %v, %v.", test.calls, test.remaining, remaining, ok)
+		}
+	}
+
+	if remaining, ok := (ForecastResponse{}).RemainingCalls(1000); ok || remaining != 0 {
+		t.Errorf("Expected an unknown call count not to report remaining calls, was %v, %v.", remaining, ok)
+	}
+}
+
+func TestForecastRequest_WithDryRun(t *testing.T) {
+	var hits int
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		hits++
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithDryRun(true).Get()
+
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if hits != 0 {
+			t.Errorf("Expected no calls to the API, got %v.", hits)
+		}
+
+		if resp.URL != testURL+"/test_key/41.8781,-87.6297?lang=en&units=us" {
+			t.Errorf("Unexpected dry run URL: %v", resp.URL)
+		}
+
+		if resp.Forecast.Currently != nil || resp.Forecast.Timezone != "" {
+			t.Errorf("Expected an empty forecast, got %+v.", resp.Forecast)
+		}
+
+		if resp := MakeRequest("", 41.8781, -87.6297).WithDryRun(true).Get(); resp.Error == nil {
+			t.Error("Expected a dry run to still be validated.")
+		}
+	})
+}
+
+func TestForecastRequest_Validate(t *testing.T) {
+	if err := MakeRequest("abc", 41.8781, -87.6297).Validate(); err != nil {
+		t.Errorf("Expected a valid request, got %v.", err)
+	}
+
+	err := MakeRequest("", 91.0, -181.0).WithUnits("metric").WithLang("klingon").Validate()
+	if err == nil {
+		t.Fatal("Expected an invalid request.")
+	}
+
+	expected := strings.Join([]string{KeyRequired, LatitudeInvalid, LongitudeInvalid, UnitsInvalid, LangInvalid}, "\n")
+	if err.Error() != expected {
+		t.Errorf("Expected every problem to be listed.\nGot: %v\nExpected: %v", err, expected)
+	}
+
+	if resp := MakeRequest("abc", 41.8781, -87.6297).WithUnits("metric").Get(); resp.Error == nil || resp.Error.Error() != UnitsInvalid {
+		t.Errorf("Expected Get to validate the request, got %v.", resp.Error)
+	}
+
+	for _, lang := range []Lang{"ja", "ko", "zh-tw", PigLatin} {
+		if err := MakeRequest("abc", 41.8781, -87.6297).WithLang(lang).Validate(); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v.", lang, err)
+		}
+	}
+
+	err = MakeRequest("abc", 41.8781, -87.6297).WithLocalTime(time.Now(), "Not/AZone").Validate()
+	if err == nil || !strings.Contains(err.Error(), "unknown time zone") {
+		t.Errorf("Expected the timezone error to be included, got %v.", err)
+	}
+}
+
 func TestForecastRequest_Get_InvalidArgs(t *testing.T) {
 	resp := MakeRequest("", 41.0, -87.62).Get()
 	if resp.Error == nil || resp.Error.Error() != KeyRequired {
@@ -66,9 +333,60 @@ func TestForecastRequest_Get_WithError(t *testing.T) {
 		if resp.Error.Error() != "A Server Error Occurred." {
 			t.Error("Error() was not the expected value.")
 		}
+
+		if resp.Duration <= 0 {
+			t.Errorf("Expected Duration to be set on error, was %v.", resp.Duration)
+		}
+
+		if resp.APICallCount != 2 {
+			t.Errorf("Expected APICallCount to be %v on error but was %v.", 2, resp.APICallCount)
+		}
 	})
 }
 
+func TestForecastRequest_Get_LargeError(t *testing.T) {
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(500)
+		resp.Write([]byte(strings.Repeat("x", 20000)))
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		if resp.Error == nil {
+			t.Fatal("Expected an HTTP Error Response to result in an error.")
+		}
+
+		if resp.Error.Error() != strings.Repeat("x", 8192)+"..." {
+			t.Errorf("Expected the error to be truncated to 8KB, was %v bytes.", len(resp.Error.Error()))
+		}
+	})
+}
+
+func TestForecastRequest_Get_NonJSON(t *testing.T) {
+	page := "<html><head><title>503 Service Unavailable</title></head><body>" + strings.Repeat("Try again later. ", 50) + "</body></html>"
+
+	for _, status := range []int{200, 503} {
+		handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			resp.Header().Set("Content-Type", "text/html")
+			resp.WriteHeader(status)
+			resp.Write([]byte(page))
+		})
+
+		usingTestServer(handler, func(testURL string) {
+			resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+			if resp.Error == nil || !strings.HasPrefix(resp.Error.Error(), NonJSONResponse+`: "<html><head><title>503`) {
+				t.Errorf("Expected a non-JSON error for a %v HTML response, got %v.", status, resp.Error)
+			}
+
+			if !strings.HasSuffix(resp.Error.Error(), `..."`) || len(resp.Error.Error()) > 300 {
+				t.Errorf("Expected the body snippet to be truncated, got %v.", resp.Error)
+			}
+		})
+	}
+}
+
 func TestForecastRequest_URL(t *testing.T) {
 	req := MakeRequest("foo", 41.1234, -81.1234)
 
@@ -97,6 +415,520 @@ func TestForecastRequest_URL(t *testing.T) {
 
 	verifyURL(req, "https://api.darksky.net/forecast/foo/41.1234,-81.1234,12345?lang=es&units=si")
 
+	req.WithLocation(-33.8688, 151.2093)
+
+	verifyURL(req, "https://api.darksky.net/forecast/foo/-33.8688,151.2093,12345?lang=es&units=si")
+
+	req.WithLocation(41.1234, -81.1234).WithTime(0)
+
+	verifyURL(req, "https://api.darksky.net/forecast/foo/41.1234,-81.1234,0?lang=es&units=si")
+
+	req.WithTime(CurrentForecast)
+
+	verifyURL(req, "https://api.darksky.net/forecast/foo/41.1234,-81.1234?lang=es&units=si")
+
+	req.Exclude = []string{"minutely", "flags"}
+	req.ExtendHourly = true
+
+	verifyURL(req, "https://api.darksky.net/forecast/foo/41.1234,-81.1234?exclude=minutely%2Cflags&extend=hourly&lang=es&units=si")
+
+	req.WithAllBlocks()
+
+	verifyURL(req, "https://api.darksky.net/forecast/foo/41.1234,-81.1234?lang=es&units=si")
+}
+
+func TestParseForecast(t *testing.T) {
+	f, err := os.Open("testdata/chicago_forecast.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	forecast, err := ParseForecast(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forecast.Timezone != "America/Chicago" || len(forecast.Hourly.Data) != 49 {
+		t.Errorf("Forecast was not parsed as expected: %v, %v hourly points.", forecast.Timezone, len(forecast.Hourly.Data))
+	}
+
+	if _, err := ParseForecast(strings.NewReader("not json")); err == nil {
+		t.Error("Expected an error parsing invalid JSON.")
+	}
+
+	if _, err := ParseForecast(strings.NewReader(`{"timezone":"America/Chicago"} {}`)); err == nil {
+		t.Errorf("Expected an error for data after the forecast, got %v.", err)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	forecast, err := Unmarshal([]byte(`{"timezone":"America/Chicago","currently":{"temperature":37.57}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forecast.Timezone != "America/Chicago" || forecast.Currently.Temperature != 37.57 {
+		t.Errorf("Forecast was not unmarshalled as expected: %+v", forecast)
+	}
+
+	if _, err := Unmarshal([]byte("{")); err == nil {
+		t.Error("Expected an error unmarshalling truncated JSON.")
+	}
+
+	data := []byte(`{"timezone":"America/Chicago"} garbage`)
+
+	if err := json.Unmarshal(data, &Forecast{}); err == nil {
+		t.Fatal("Expected json.Unmarshal to reject trailing data.")
+	}
+
+	if _, err := Unmarshal(data); err == nil {
+		t.Error("Expected an error unmarshalling JSON with trailing data, as json.Unmarshal does.")
+	}
+}
+
+func TestForecast_MissingBlocks(t *testing.T) {
+	forecast, err := decodeForecast([]byte(`{"latitude":41.8781,"longitude":-87.6297,"currently":{"time":1451362625}}`), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forecast.Currently == nil || forecast.Currently.Time != 1451362625 {
+		t.Error("Expected Currently to be populated.")
+	}
+
+	if forecast.Minutely != nil || forecast.Hourly != nil || forecast.Daily != nil || forecast.Flags != nil {
+		t.Error("Expected absent blocks to be nil.")
+	}
+
+	data, err := json.Marshal(forecast)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range []string{"minutely", "hourly", "daily", "flags"} {
+		if strings.Contains(string(data), `"`+block+`"`) {
+			t.Errorf("Expected %v to be omitted from %s.", block, data)
+		}
+	}
+}
+
+func TestForecast_HasBlocks(t *testing.T) {
+	var forecast *Forecast
+
+	if forecast.HasCurrently() || forecast.HasMinutely() || forecast.HasHourly() || forecast.HasDaily() {
+		t.Error("Expected a nil Forecast to have no blocks.")
+	}
+
+	forecast = &Forecast{Currently: &DataPoint{}, Hourly: &DataBlock{}}
+
+	if !forecast.HasCurrently() || !forecast.HasHourly() {
+		t.Error("Expected Currently and Hourly to be present.")
+	}
+
+	if forecast.HasMinutely() || forecast.HasDaily() {
+		t.Error("Expected Minutely and Daily to be absent.")
+	}
+}
+
+func TestForecast_Location(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	loc, err := forecast.Location()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loc.String() != "America/Chicago" {
+		t.Errorf("Expected America/Chicago, got %v.", loc)
+	}
+
+	if again, _ := forecast.Location(); again != loc {
+		t.Error("Expected the loaded location to be cached.")
+	}
+
+	forecast.Timezone = "Not/A_Zone"
+
+	loc, err = forecast.Location()
+	if err == nil {
+		t.Error("Expected an error for an unknown timezone.")
+	}
+
+	if _, offset := time.Unix(forecast.Currently.Time, 0).In(loc).Zone(); offset != -6*3600 {
+		t.Errorf("Expected a fixed zone from Offset, got offset %v.", offset)
+	}
+
+	forecast.Timezone = ""
+
+	if _, err := forecast.Location(); err == nil || err.Error() != TimezoneMissing {
+		t.Errorf("Expected a missing timezone to be an error, got %v.", err)
+	}
+}
+
+func TestForecast_IssuedAt(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if issued := forecast.IssuedAt(); issued.Unix() != 1451362625 || issued.Location().String() != "America/Chicago" {
+		t.Errorf("Expected the currently time in Chicago, got %v.", issued)
+	}
+
+	forecast.Currently = nil
+
+	// The daily block starts at midnight, before the first minutely and hourly points.
+	if issued := forecast.IssuedAt(); issued.Unix() != forecast.Daily.Data[0].Time {
+		t.Errorf("Expected the earliest block time, got %v.", issued)
+	}
+
+	forecast.Minutely, forecast.Hourly, forecast.Daily = nil, nil, nil
+
+	if !forecast.IssuedAt().IsZero() || !(*Forecast)(nil).IssuedAt().IsZero() {
+		t.Error("Expected the zero time without any data points.")
+	}
+}
+
+func TestForecast_Today(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	// Currently is 2015-12-29 UTC, but still the evening of 2015-12-28 in Chicago.
+	today, ok := forecast.Today()
+	if !ok || today.Time != forecast.Daily.Data[0].Time {
+		t.Errorf("Expected the first daily data point, got %v, %v.", today.Time, ok)
+	}
+
+	forecast.Currently.Time += 24 * 60 * 60
+
+	today, ok = forecast.Today()
+	if !ok || today.Time != forecast.Daily.Data[1].Time {
+		t.Errorf("Expected the second daily data point, got %v, %v.", today.Time, ok)
+	}
+
+	forecast.Currently.Time += 30 * 24 * 60 * 60
+
+	if _, ok := forecast.Today(); ok {
+		t.Error("Expected no daily data point outside of the daily block.")
+	}
+
+	defer func(now func() time.Time) { Now = now }(Now)
+	Now = func() time.Time { return time.Unix(forecast.Daily.Data[3].Time+12*60*60, 0) }
+
+	forecast.Currently = nil
+
+	if today, ok := forecast.Today(); !ok || today.Time != forecast.Daily.Data[3].Time {
+		t.Errorf("Expected Now to be used without currently, got %v, %v.", today.Time, ok)
+	}
+
+	if _, ok := (*Forecast)(nil).Today(); ok {
+		t.Error("Expected no daily data point for a nil forecast.")
+	}
+}
+
+func TestForecast_RainStartsWithin(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if _, ok := forecast.RainStartsWithin(time.Hour); ok {
+		t.Error("Expected no rain above the default threshold.")
+	}
+
+	// The first minutely point is a few seconds before currently, so it's outside the window.
+	forecast.Minutely.Data[0].PrecipProbability = 0.9
+	forecast.Minutely.Data[10].PrecipProbability = 0.6
+	forecast.Minutely.Data[20].PrecipProbability = 0.8
+
+	start, ok := forecast.RainStartsWithin(time.Hour)
+	if !ok || start.Unix() != forecast.Minutely.Data[10].Time {
+		t.Errorf("Expected rain to start at the eleventh minutely point, got %v, %v.", start, ok)
+	}
+
+	if start.Location().String() != forecast.Timezone {
+		t.Errorf("Expected the start time in %v, got %v.", forecast.Timezone, start.Location())
+	}
+
+	if _, ok := forecast.RainStartsWithin(5 * time.Minute); ok {
+		t.Error("Expected no rain within five minutes.")
+	}
+
+	if start, ok := forecast.RainStartsWithinThreshold(time.Hour, 0.7); !ok || start.Unix() != forecast.Minutely.Data[20].Time {
+		t.Errorf("Expected the given threshold to be used, got %v, %v.", start, ok)
+	}
+
+	defer func(now func() time.Time) { Now = now }(Now)
+	Now = func() time.Time { return time.Unix(forecast.Minutely.Data[15].Time, 0) }
+
+	forecast.Currently = nil
+
+	if start, ok := forecast.RainStartsWithinThreshold(time.Minute, 0.7); ok {
+		t.Errorf("Expected Now to be used without currently, got %v.", start)
+	}
+
+	if start, ok := forecast.RainStartsWithinThreshold(10*time.Minute, 0.7); !ok || start.Unix() != forecast.Minutely.Data[20].Time {
+		t.Errorf("Expected rain five minutes after Now, got %v, %v.", start, ok)
+	}
+
+	if _, ok := (*Forecast)(nil).RainStartsWithin(time.Hour); ok {
+		t.Error("Expected no rain for a nil forecast.")
+	}
+}
+
+func TestForecast_EachDataPoint(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	counts := map[string]int{}
+
+	forecast.EachDataPoint(func(block string, dp DataPoint) {
+		counts[block]++
+	})
+
+	expected := map[string]int{"currently": 1, "minutely": 61, "hourly": 49, "daily": 8}
+
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v data points, got %v.", expected, counts)
+	}
+
+	forecast.Currently = nil
+	forecast.Minutely = nil
+	counts = map[string]int{}
+
+	forecast.EachDataPoint(func(block string, dp DataPoint) {
+		counts[block]++
+	})
+
+	if counts["currently"] != 0 || counts["minutely"] != 0 || counts["hourly"] != 49 {
+		t.Errorf("Expected missing blocks to be skipped, got %v.", counts)
+	}
+}
+
+func TestForecast_IsDegraded(t *testing.T) {
+	forecast := loadForecast(t, "testdata/unavailable_forecast.json")
+
+	if !forecast.IsDegraded() {
+		t.Error("Expected the forecast to be degraded.")
+	}
+
+	if forecast.DegradedReason() != "The Dark Sky data source is unavailable for this location." {
+		t.Errorf("Unexpected DegradedReason: %v", forecast.DegradedReason())
+	}
+
+	forecast = loadForecast(t, "testdata/chicago_forecast.json")
+
+	if forecast.IsDegraded() || forecast.DegradedReason() != "" {
+		t.Error("Expected the Chicago forecast not to be degraded.")
+	}
+
+	forecast.Flags = nil
+
+	if forecast.IsDegraded() {
+		t.Error("Expected a forecast without flags not to be degraded.")
+	}
+}
+
+func TestForecast_LocalTime(t *testing.T) {
+	forecast := Forecast{Timezone: "America/Chicago", Offset: -6}
+
+	lt := forecast.LocalTime(1451362625)
+
+	if lt.Format(time.RFC3339) != "2015-12-28T22:17:05-06:00" {
+		t.Errorf("Expected local time to be 2015-12-28T22:17:05-06:00, was %v.", lt.Format(time.RFC3339))
+	}
+}
+
+func TestForecastRequest_WithLocalTime(t *testing.T) {
+	// Noon on Dec 28th in Chicago, regardless of the zone of the given time.
+	noon := time.Date(2015, 12, 28, 12, 0, 0, 0, time.UTC)
+
+	req := MakeRequest("foo", 41.8781, -87.6297).WithLocalTime(noon, "America/Chicago")
+
+	if req.Time != 1451325600 {
+		t.Errorf("Expected noon in Chicago to be %v, was %v.", 1451325600, req.Time)
+	}
+
+	req.WithLocalTime(noon, "Not/A_Zone")
+
+	if _, err := req.URL(); err == nil {
+		t.Error("Expected an invalid timezone to result in an error.")
+	}
+
+	if resp := req.Get(); resp.Error == nil {
+		t.Error("Expected Get to return the invalid timezone error.")
+	}
+
+	usingTestServer(validForecastHandler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithLocalTime(noon, "Not/A_Zone").WithTime(1000).Get()
+		if resp.Error != nil {
+			t.Errorf("Expected WithTime to replace the time that failed to resolve, got %v.", resp.Error)
+		}
+	})
+}
+
+func TestForecastRequest_Clone(t *testing.T) {
+	template := MakeRequest("foo", 41.8781, -87.6297).WithUnits(SI).WithHeader("X-Tag", "template")
+	template.Exclude = []string{"minutely"}
+
+	clone := template.Clone()
+
+	if !reflect.DeepEqual(clone, template) {
+		t.Errorf("Expected the clone to equal the template.\nGot: %+v\nExpected: %+v", clone, template)
+	}
+
+	clone.WithLocation(-33.8688, 151.2093).WithHeader("X-Tag", "clone")
+	clone.Exclude[0] = "hourly"
+	clone.Exclude = append(clone.Exclude, "daily")
+
+	if template.Lat != 41.8781 || template.Lng != -87.6297 {
+		t.Error("Expected the template location to be unchanged.")
+	}
+
+	if !reflect.DeepEqual(template.Exclude, []string{"minutely"}) {
+		t.Errorf("Expected the template Exclude to be independent, was %v.", template.Exclude)
+	}
+
+	if !reflect.DeepEqual(template.header["X-Tag"], []string{"template"}) {
+		t.Errorf("Expected the template headers to be independent, was %v.", template.header)
+	}
+}
+
+func TestForecastRequest_WithQueryParam(t *testing.T) {
+	req := MakeRequest("foo", 41.1234, -81.1234).WithQueryParam("version", "2").WithQueryParam("units", "si").WithQueryParam("tag", "a").WithQueryParam("tag", "b")
+
+	u, err := req.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u != "https://api.darksky.net/forecast/foo/41.1234,-81.1234?lang=en&tag=a&tag=b&units=us&version=2" {
+		t.Errorf("Expected extra params to be added without overriding units, got %v.", u)
+	}
+
+	clone := req.Clone().WithQueryParam("version", "3")
+
+	if reqURL, _ := req.URL(); reqURL != u {
+		t.Errorf("Expected the original query params to be independent of the clone, got %v.", reqURL)
+	}
+
+	if cloneURL, _ := clone.URL(); !strings.Contains(cloneURL, "version=2&version=3") {
+		t.Errorf("Expected the clone to keep the original query params, got %v.", cloneURL)
+	}
+}
+
+func TestForecastRequest_WithExclude(t *testing.T) {
+	exclude := []string{"minutely", "flags"}
+
+	req := MakeRequest("foo", 41.1234, -81.1234).WithExclude(exclude)
+
+	exclude[0] = "currently"
+
+	if !reflect.DeepEqual(req.Exclude, []string{"minutely", "flags"}) {
+		t.Errorf("Expected Exclude to be unaffected by changes to the argument, was %v.", req.Exclude)
+	}
+
+	u, err := req.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u != "https://api.darksky.net/forecast/foo/41.1234,-81.1234?exclude=minutely%2Cflags&lang=en&units=us" {
+		t.Errorf("Unexpected URL: %v", u)
+	}
+}
+
+func TestForecastRequest_WithOnly(t *testing.T) {
+	req := MakeRequest("foo", 41.1234, -81.1234).WithOnly(CurrentlyBlock, HourlyBlock)
+
+	if !reflect.DeepEqual(req.Exclude, []string{"minutely", "daily", "alerts", "flags"}) {
+		t.Errorf("Expected every other block to be excluded, got %v.", req.Exclude)
+	}
+
+	req.WithOnly()
+
+	if len(req.Exclude) != 6 {
+		t.Errorf("Expected every block to be excluded, got %v.", req.Exclude)
+	}
+}
+
+func TestParseRequestURL(t *testing.T) {
+	req := MakeRequest("foo", 41.1234, -81.1234).WithBaseURL("http://localhost:8080/proxy/forecast").WithTime(12345).WithLang(Spanish).WithUnits(SI).WithQueryParam("version", "2")
+	req.Exclude = []string{"minutely", "alerts"}
+	req.ExtendHourly = true
+
+	u, err := req.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseRequestURL(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, req) {
+		t.Errorf("Got: %+v\nExpected: %+v", parsed, req)
+	}
+
+	parsed, err = ParseRequestURL("https://api.darksky.net/forecast/foo/41.1234,-81.1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, MakeRequest("foo", 41.1234, -81.1234)) {
+		t.Errorf("Expected defaults for a URL without a time or query, got %+v", parsed)
+	}
+
+	invalid := []string{
+		"https://api.darksky.net/forecast",
+		"https://api.darksky.net/forecast/foo/41.1234",
+		"https://api.darksky.net/forecast/foo/north,-81.1234",
+		"https://api.darksky.net/forecast/foo/41.1234,-81.1234,yesterday",
+		"https://api.darksky.net/forecast//41.1234,-81.1234",
+	}
+
+	for _, raw := range invalid {
+		if _, err := ParseRequestURL(raw); err == nil || err.Error() != RequestURLInvalid {
+			t.Errorf("Expected %v to be invalid, got %v.", raw, err)
+		}
+	}
+}
+
+func TestFlags_AllStations(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	stations := forecast.Flags.AllStations()
+
+	if len(stations) != 31 {
+		t.Errorf("Expected 31 stations, got %v: %v", len(stations), stations)
+	}
+
+	if len(forecast.Flags.ISDStations) != 5 || len(forecast.Flags.MADISStations) != 16 {
+		t.Errorf("Expected 5 ISD and 16 MADIS stations, got %v and %v.", len(forecast.Flags.ISDStations), len(forecast.Flags.MADISStations))
+	}
+
+	if stations[0] != "KLOT" || stations[len(stations)-1] != "UR355" {
+		t.Errorf("Expected stations in field order, got %v.", stations)
+	}
+
+	var flags *Flags
+
+	if flags.AllStations() != nil {
+		t.Error("Expected nil Flags to have no stations.")
+	}
+}
+
+func TestFlags_IsUnavailable(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if forecast.Flags.IsUnavailable() {
+		t.Error("Expected the Chicago forecast to be available.")
+	}
+
+	flags := &Flags{DarkSkyUnavailable: "The Dark Sky data source is unavailable for this location."}
+
+	if !flags.IsUnavailable() {
+		t.Error("Expected the flags to be unavailable.")
+	}
+
+	flags = nil
+
+	if flags.IsUnavailable() {
+		t.Error("Expected nil Flags to be available.")
+	}
 }
 
 func TestDataPoint_WindDirection(t *testing.T) {
@@ -129,10 +961,55 @@ var validForecastHandler http.HandlerFunc = http.HandlerFunc(func(resp http.Resp
 })
 
 var errorForecastHandler http.HandlerFunc = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Add(APICallsHeader, "2")
 	resp.WriteHeader(500)
 	resp.Write([]byte("A Server Error Occurred."))
 })
 
+func BenchmarkDecodeForecast(b *testing.B) {
+	jsonBytes, err := ioutil.ReadFile("testdata/chicago_forecast.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeForecast(jsonBytes, false, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeForecast_Extra(b *testing.B) {
+	jsonBytes, err := ioutil.ReadFile("testdata/chicago_forecast.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeForecast(jsonBytes, false, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func loadForecast(t *testing.T, path string) *Forecast {
+	jsonBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forecast, err := Unmarshal(jsonBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return forecast
+}
+
 func usingTestServer(handler http.HandlerFunc, runTest func(testURL string)) {
 	ts := httptest.NewServer(handler)
 
@@ -142,3 +1019,27 @@ func usingTestServer(handler http.HandlerFunc, runTest func(testURL string)) {
 
 	runTest(ts.URL)
 }
+
+func BenchmarkForecastRequest_Get(b *testing.B) {
+	jsonBytes, err := ioutil.ReadFile("testdata/chicago_forecast.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Write(jsonBytes)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		req := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if resp := req.Get(); resp.Error != nil {
+				b.Fatal(resp.Error)
+			}
+		}
+	})
+}
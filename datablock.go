@@ -0,0 +1,363 @@
+package darksky
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// AggregateDaily groups the block's data points, typically the hourly block, by calendar day in
+// loc and produces one synthetic daily DataPoint per day in chronological order. A nil loc is
+// treated as UTC. The data points are expected to be sorted by Time, as returned by the API.
+//
+// Only the following fields are aggregated, all others are left at their zero value:
+//
+//	Time                                   midnight at the start of the day in loc
+//	TemperatureMax, TemperatureMaxTime     highest Temperature of the day and when it occurred
+//	TemperatureMin, TemperatureMinTime     lowest Temperature of the day and when it occurred
+//	Humidity                               mean Humidity of the day
+//	PrecipAccumulation                     sum of PrecipAccumulation for the day
+func (db *DataBlock) AggregateDaily(loc *time.Location) []DataPoint {
+	if db == nil || len(db.Data) == 0 {
+		return nil
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var days []DataPoint
+	var counts []int
+
+	for _, dp := range db.Data {
+		t := time.Unix(dp.Time, 0).In(loc)
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Unix()
+
+		if len(days) == 0 || days[len(days)-1].Time != start {
+			days = append(days, DataPoint{
+				Time:               start,
+				TemperatureMax:     dp.Temperature,
+				TemperatureMaxTime: dp.Time,
+				TemperatureMin:     dp.Temperature,
+				TemperatureMinTime: dp.Time,
+			})
+			counts = append(counts, 0)
+		}
+
+		day := &days[len(days)-1]
+		counts[len(counts)-1]++
+
+		if dp.Temperature > day.TemperatureMax {
+			day.TemperatureMax = dp.Temperature
+			day.TemperatureMaxTime = dp.Time
+		}
+
+		if dp.Temperature < day.TemperatureMin {
+			day.TemperatureMin = dp.Temperature
+			day.TemperatureMinTime = dp.Time
+		}
+
+		day.Humidity += dp.Humidity
+		day.PrecipAccumulation += dp.PrecipAccumulation
+	}
+
+	for i := range days {
+		days[i].Humidity /= float64(counts[i])
+	}
+
+	return days
+}
+
+// Nearest returns the data point whose Time is closest to t. When two points are equally close the
+// earlier one is returned. The bool is false if the block is nil or has no data points.
+func (db *DataBlock) Nearest(t time.Time) (DataPoint, bool) {
+	if db == nil || len(db.Data) == 0 {
+		return DataPoint{}, false
+	}
+
+	target := t.Unix()
+	nearest := db.Data[0]
+
+	for _, dp := range db.Data[1:] {
+		d, best := abs(dp.Time-target), abs(nearest.Time-target)
+
+		if d < best || (d == best && dp.Time < nearest.Time) {
+			nearest = dp
+		}
+	}
+
+	return nearest, true
+}
+
+// InterpolateTemperature estimates Temperature at t by linear interpolation between the two data
+// points either side of it. Only linear interpolation is done. The bool is false if t is before
+// the first or after the last data point. The data points are expected to be sorted by Time.
+func (db *DataBlock) InterpolateTemperature(t time.Time) (float64, bool) {
+	if db == nil || len(db.Data) == 0 {
+		return 0, false
+	}
+
+	ts := float64(t.UnixNano()) / float64(time.Second)
+
+	for i, dp := range db.Data {
+		if ts == float64(dp.Time) {
+			return dp.Temperature, true
+		}
+
+		if i == 0 || ts > float64(dp.Time) {
+			continue
+		}
+
+		prev := db.Data[i-1]
+		if ts < float64(prev.Time) {
+			return 0, false
+		}
+
+		frac := (ts - float64(prev.Time)) / float64(dp.Time-prev.Time)
+		return prev.Temperature + frac*(dp.Temperature-prev.Temperature), true
+	}
+
+	return 0, false
+}
+
+// TotalPrecipAccumulation returns the sum of PrecipAccumulation across the data points. The
+// accumulation values are summed directly, not derived from PrecipIntensity. Note the API only
+// reports accumulation for snow, so this is zero for rain. Zero is returned for an empty block.
+func (db *DataBlock) TotalPrecipAccumulation() float64 {
+	if db == nil {
+		return 0
+	}
+
+	total := 0.0
+	for _, dp := range db.Data {
+		total += dp.PrecipAccumulation
+	}
+
+	return total
+}
+
+// MeanPrecipIntensity returns the mean PrecipIntensity across the data points. For the hourly block
+// this multiplied by the number of hours estimates the total precipitation expected. Zero is
+// returned for an empty block.
+func (db *DataBlock) MeanPrecipIntensity() float64 {
+	if db == nil || len(db.Data) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, dp := range db.Data {
+		total += dp.PrecipIntensity
+	}
+
+	return total / float64(len(db.Data))
+}
+
+// MaxPrecipProbability returns the highest PrecipProbability across the data points, along with the
+// first data point to reach it, such as the wettest day of the daily block. Zero and an empty
+// DataPoint are returned for an empty block.
+func (db *DataBlock) MaxPrecipProbability() (float64, DataPoint) {
+	if db == nil || len(db.Data) == 0 {
+		return 0, DataPoint{}
+	}
+
+	max := db.Data[0]
+	for _, dp := range db.Data[1:] {
+		if dp.PrecipProbability > max.PrecipProbability {
+			max = dp
+		}
+	}
+
+	return max.PrecipProbability, max
+}
+
+// MeanCloudCover returns the mean CloudCover across the data points, from 0 to 1. Zero is returned
+// for an empty block.
+func (db *DataBlock) MeanCloudCover() float64 {
+	if db == nil || len(db.Data) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, dp := range db.Data {
+		total += dp.CloudCover
+	}
+
+	return total / float64(len(db.Data))
+}
+
+// MeanCloudCoverBetween returns the mean CloudCover of the data points from start up to, but not
+// including, end, such as the daylight hours of the hourly block. Zero is returned when no data
+// points fall in the range.
+func (db *DataBlock) MeanCloudCoverBetween(start time.Time, end time.Time) float64 {
+	if db == nil {
+		return 0
+	}
+
+	total, n := 0.0, 0
+	for _, dp := range db.Data {
+		if t := time.Unix(dp.Time, 0); !t.Before(start) && t.Before(end) {
+			total += dp.CloudCover
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return total / float64(n)
+}
+
+// sparkBars are the characters used by PrecipSparkline, from lowest to highest.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// PrecipSparkline renders PrecipIntensity across the data points as a sparkline of width block
+// characters, such as "▁▁▂▄▇█▅▂", scaled so that no precipitation is the lowest bar and the
+// heaviest intensity in the block is the highest. Each character shows the heaviest intensity of
+// the data points it covers, repeating points when width is more than the number of points. An
+// empty block, or one without precipitation, renders as a flat line.
+func (db *DataBlock) PrecipSparkline(width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	var data []DataPoint
+	if db != nil {
+		data = db.Data
+	}
+
+	max := 0.0
+	for _, dp := range data {
+		if dp.PrecipIntensity > max {
+			max = dp.PrecipIntensity
+		}
+	}
+
+	line := make([]rune, width)
+
+	for i := range line {
+		line[i] = sparkBars[0]
+
+		if max <= 0 {
+			continue
+		}
+
+		start, end := i*len(data)/width, (i+1)*len(data)/width
+		if end <= start {
+			end = start + 1
+		}
+
+		v := 0.0
+		for _, dp := range data[start:end] {
+			if dp.PrecipIntensity > v {
+				v = dp.PrecipIntensity
+			}
+		}
+
+		line[i] = sparkBars[int(v/max*float64(len(sparkBars)-1)+0.5)]
+	}
+
+	return string(line)
+}
+
+// PrecipTransition is a change in PrecipType between consecutive data points of a DataBlock. From
+// or To is empty when precipitation stops or starts.
+type PrecipTransition struct {
+	Time int64  `json:"time"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PrecipTransitions returns a PrecipTransition for every data point whose PrecipType differs from
+// the previous point's, such as rain changing to snow, with Time being the time of the point the
+// new type starts at. Nil is returned for a nil block, a block with fewer than two data points, or
+// one whose PrecipType never changes.
+func (db *DataBlock) PrecipTransitions() []PrecipTransition {
+	if db == nil || len(db.Data) < 2 {
+		return nil
+	}
+
+	var transitions []PrecipTransition
+
+	for i := 1; i < len(db.Data); i++ {
+		prev, dp := db.Data[i-1], db.Data[i]
+
+		if dp.PrecipType != prev.PrecipType {
+			transitions = append(transitions, PrecipTransition{Time: dp.Time, From: prev.PrecipType, To: dp.PrecipType})
+		}
+	}
+
+	return transitions
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// csvHeader is the header row written by WriteCSV, using the JSON field names.
+var csvHeader = []string{
+	"time", "summary", "icon", "temperature", "apparentTemperature", "dewPoint", "humidity",
+	"precipIntensity", "precipProbability", "precipType", "windSpeed", "windBearing",
+	"cloudCover", "pressure", "visibility",
+}
+
+// WriteCSV writes the block's data points to w as CSV, with a header row followed by one row per
+// DataPoint containing the common fields. Times are written as seconds since epoch, use WriteCSVIn
+// for RFC3339 times.
+func (db *DataBlock) WriteCSV(w io.Writer) error {
+	return db.WriteCSVIn(w, nil)
+}
+
+// WriteCSVIn is WriteCSV with times written as RFC3339 in loc, or as seconds since epoch when loc
+// is nil.
+func (db *DataBlock) WriteCSVIn(w io.Writer, loc *time.Location) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	if db != nil {
+		for _, dp := range db.Data {
+			ts := strconv.FormatInt(dp.Time, 10)
+			if loc != nil {
+				ts = time.Unix(dp.Time, 0).In(loc).Format(time.RFC3339)
+			}
+
+			row := []string{
+				ts,
+				dp.Summary,
+				dp.Icon,
+				formatFloat(dp.Temperature),
+				formatFloat(dp.ApparentTemperature),
+				formatFloat(dp.DewPoint),
+				formatFloat(dp.Humidity),
+				formatFloat(dp.PrecipIntensity),
+				formatFloat(dp.PrecipProbability),
+				dp.PrecipType,
+				formatFloat(dp.WindSpeed),
+				formatFloat(dp.WindBearing),
+				formatFloat(dp.CloudCover),
+				formatFloat(dp.Pressure),
+				formatFloat(dp.Visibility),
+			}
+
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,325 @@
+package darksky
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestDataBlock_AggregateDaily(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	loc, err := time.LoadLocation(forecast.Timezone)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	days := forecast.Hourly.AggregateDaily(loc)
+
+	if len(days) != 3 {
+		t.Fatalf("Expected 49 hours to span 3 days, got %v.", len(days))
+	}
+
+	if days[0].Time != 1451282400 {
+		t.Errorf("Expected the first day to start at %v, was %v.", 1451282400, days[0].Time)
+	}
+
+	block := DataBlock{Data: []DataPoint{
+		{Time: 0, Temperature: 10, Humidity: 0.5, PrecipAccumulation: 0.1},
+		{Time: 3600, Temperature: 15, Humidity: 0.7, PrecipAccumulation: 0.2},
+		{Time: 7200, Temperature: 5, Humidity: 0.9},
+		{Time: 86400, Temperature: 20, Humidity: 0.4},
+	}}
+
+	days = block.AggregateDaily(nil)
+
+	if len(days) != 2 {
+		t.Fatalf("Expected 2 days, got %v.", len(days))
+	}
+
+	day := days[0]
+
+	if day.TemperatureMax != 15 || day.TemperatureMaxTime != 3600 {
+		t.Errorf("Expected a high of 15 at 3600, was %v at %v.", day.TemperatureMax, day.TemperatureMaxTime)
+	}
+
+	if day.TemperatureMin != 5 || day.TemperatureMinTime != 7200 {
+		t.Errorf("Expected a low of 5 at 7200, was %v at %v.", day.TemperatureMin, day.TemperatureMinTime)
+	}
+
+	if day.Humidity < 0.69 || day.Humidity > 0.71 {
+		t.Errorf("Expected mean humidity of 0.7, was %v.", day.Humidity)
+	}
+
+	if day.PrecipAccumulation < 0.29 || day.PrecipAccumulation > 0.31 {
+		t.Errorf("Expected total accumulation of 0.3, was %v.", day.PrecipAccumulation)
+	}
+
+	if days[1].Time != 86400 || days[1].TemperatureMax != 20 {
+		t.Errorf("Expected the second day to start at 86400 with a high of 20, was %+v.", days[1])
+	}
+
+	var empty *DataBlock
+
+	if empty.AggregateDaily(nil) != nil {
+		t.Error("Expected a nil block to aggregate to nil.")
+	}
+}
+
+func TestDataBlock_WriteCSV(t *testing.T) {
+	block := DataBlock{Data: []DataPoint{
+		{Time: 1451361600, Summary: "Light Rain", Icon: "rain", Temperature: 37.33, Humidity: 0.94, PrecipType: "rain"},
+		{Time: 1451365200, Summary: "Drizzle, with, commas", Temperature: 37.5},
+	}}
+
+	var buf bytes.Buffer
+
+	if err := block.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got:\n%v", buf.String())
+	}
+
+	if !strings.HasPrefix(lines[0], "time,summary,icon,temperature,") {
+		t.Errorf("Unexpected header row: %v", lines[0])
+	}
+
+	if !strings.HasPrefix(lines[1], "1451361600,Light Rain,rain,37.33,0,0,0.94,") {
+		t.Errorf("Unexpected row: %v", lines[1])
+	}
+
+	if !strings.HasPrefix(lines[2], `1451365200,"Drizzle, with, commas",`) {
+		t.Errorf("Expected the summary to be quoted: %v", lines[2])
+	}
+
+	buf.Reset()
+
+	if err := block.WriteCSVIn(&buf, time.UTC); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "\n2015-12-29T04:00:00Z,") {
+		t.Errorf("Expected RFC3339 times when a location is given:\n%v", buf.String())
+	}
+}
+
+func TestDataBlock_Nearest(t *testing.T) {
+	block := &DataBlock{Data: []DataPoint{{Time: 0}, {Time: 3600}, {Time: 7200}}}
+
+	tests := []struct {
+		t        int64
+		expected int64
+	}{
+		{-500, 0},
+		{1000, 0},
+		{1800, 0},
+		{1801, 3600},
+		{5400, 3600},
+		{9000, 7200},
+	}
+
+	for _, test := range tests {
+		dp, ok := block.Nearest(time.Unix(test.t, 0))
+
+		if !ok || dp.Time != test.expected {
+			t.Errorf("Expected the nearest point to %v to be %v, was %v.", test.t, test.expected, dp.Time)
+		}
+	}
+
+	block = &DataBlock{}
+
+	if _, ok := block.Nearest(time.Unix(0, 0)); ok {
+		t.Error("Expected an empty block not to have a nearest point.")
+	}
+
+	block = nil
+
+	if _, ok := block.Nearest(time.Unix(0, 0)); ok {
+		t.Error("Expected a nil block not to have a nearest point.")
+	}
+}
+
+func TestDataBlock_InterpolateTemperature(t *testing.T) {
+	block := &DataBlock{Data: []DataPoint{
+		{Time: 0, Temperature: 30},
+		{Time: 3600, Temperature: 40},
+		{Time: 7200, Temperature: 35},
+	}}
+
+	tests := []struct {
+		t        time.Time
+		expected float64
+	}{
+		{time.Unix(0, 0), 30},
+		{time.Unix(1800, 0), 35},
+		{time.Unix(900, 0), 32.5},
+		{time.Unix(3600, 0), 40},
+		{time.Unix(5400, 0), 37.5},
+		{time.Unix(7200, 0), 35},
+		{time.Unix(1800, int64(180*time.Second)), 35.5},
+	}
+
+	for _, test := range tests {
+		temp, ok := block.InterpolateTemperature(test.t)
+
+		if !ok || !closeTo(temp, test.expected) {
+			t.Errorf("Expected the temperature at %v to be %v, was %v.", test.t.Unix(), test.expected, temp)
+		}
+	}
+
+	for _, outside := range []int64{-1, 7201} {
+		if _, ok := block.InterpolateTemperature(time.Unix(outside, 0)); ok {
+			t.Errorf("Expected %v to be outside the block.", outside)
+		}
+	}
+}
+
+func TestDataBlock_Precip(t *testing.T) {
+	block := &DataBlock{Data: []DataPoint{
+		{PrecipIntensity: 0.01, PrecipAccumulation: 0.2},
+		{PrecipIntensity: 0.03, PrecipAccumulation: 0.5},
+		{PrecipIntensity: 0.05},
+	}}
+
+	if !closeTo(block.TotalPrecipAccumulation(), 0.7) {
+		t.Errorf("Expected total accumulation of 0.7, was %v.", block.TotalPrecipAccumulation())
+	}
+
+	if !closeTo(block.MeanPrecipIntensity(), 0.03) {
+		t.Errorf("Expected mean intensity of 0.03, was %v.", block.MeanPrecipIntensity())
+	}
+
+	block = &DataBlock{}
+
+	if block.TotalPrecipAccumulation() != 0 || block.MeanPrecipIntensity() != 0 {
+		t.Error("Expected an empty block to have no precipitation.")
+	}
+
+	block = nil
+
+	if block.TotalPrecipAccumulation() != 0 || block.MeanPrecipIntensity() != 0 {
+		t.Error("Expected a nil block to have no precipitation.")
+	}
+}
+
+func TestDataBlock_PrecipSparkline(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	for _, width := range []int{1, 20, 60, 100} {
+		line := forecast.Minutely.PrecipSparkline(width)
+
+		if utf8.RuneCountInString(line) != width {
+			t.Errorf("Expected a sparkline of width %v, got %q.", width, line)
+		}
+	}
+
+	db := &DataBlock{Data: []DataPoint{{PrecipIntensity: 0}, {PrecipIntensity: 0.05}, {PrecipIntensity: 0.1}, {PrecipIntensity: 0.025}}}
+
+	if line := db.PrecipSparkline(4); line != "▁▅█▃" {
+		t.Errorf("Expected the sparkline to be scaled to the heaviest intensity, got %q.", line)
+	}
+
+	if line := db.PrecipSparkline(2); line != "▅█" {
+		t.Errorf("Expected the heaviest intensity of each column, got %q.", line)
+	}
+
+	if line := db.PrecipSparkline(8); line != "▁▁▅▅██▃▃" {
+		t.Errorf("Expected points to be repeated to fill the width, got %q.", line)
+	}
+
+	var empty *DataBlock
+
+	if line := empty.PrecipSparkline(5); line != "▁▁▁▁▁" {
+		t.Errorf("Expected an empty block to render flat, got %q.", line)
+	}
+
+	if line := (&DataBlock{Data: make([]DataPoint, 3)}).PrecipSparkline(3); line != "▁▁▁" {
+		t.Errorf("Expected no precipitation to render flat, got %q.", line)
+	}
+}
+
+func TestDataBlock_MeanCloudCover(t *testing.T) {
+	db := &DataBlock{Data: []DataPoint{
+		{Time: 1451365200, CloudCover: 0.2},
+		{Time: 1451368800, CloudCover: 0.4},
+		{Time: 1451372400, CloudCover: 0.9},
+		{Time: 1451376000, CloudCover: 0.5},
+	}}
+
+	if !closeTo(db.MeanCloudCover(), 0.5) {
+		t.Errorf("Expected a mean cloud cover of 0.5, got %v.", db.MeanCloudCover())
+	}
+
+	mean := db.MeanCloudCoverBetween(time.Unix(1451368800, 0), time.Unix(1451376000, 0))
+	if !closeTo(mean, 0.65) {
+		t.Errorf("Expected a mean cloud cover of 0.65 excluding the end, got %v.", mean)
+	}
+
+	if mean := db.MeanCloudCoverBetween(time.Unix(0, 0), time.Unix(3600, 0)); mean != 0 {
+		t.Errorf("Expected zero when no data points are in range, got %v.", mean)
+	}
+
+	var empty *DataBlock
+
+	if empty.MeanCloudCover() != 0 || (&DataBlock{}).MeanCloudCover() != 0 {
+		t.Error("Expected zero for an empty block.")
+	}
+}
+
+func TestDataBlock_MaxPrecipProbability(t *testing.T) {
+	db := &DataBlock{Data: []DataPoint{
+		{Time: 1451282400, PrecipProbability: 0.2},
+		{Time: 1451368800, PrecipProbability: 0.7},
+		{Time: 1451455200, PrecipProbability: 0.7},
+		{Time: 1451541600, PrecipProbability: 0.1},
+	}}
+
+	if p, dp := db.MaxPrecipProbability(); p != 0.7 || dp.Time != 1451368800 {
+		t.Errorf("Expected the first point with the peak of 0.7, got %v at %v.", p, dp.Time)
+	}
+
+	var empty *DataBlock
+
+	if p, dp := empty.MaxPrecipProbability(); p != 0 || dp.Time != 0 {
+		t.Errorf("Expected zero for an empty block, got %v at %v.", p, dp.Time)
+	}
+}
+
+func TestDataBlock_PrecipTransitions(t *testing.T) {
+	db := &DataBlock{Data: []DataPoint{
+		{Time: 1451365200, PrecipType: "rain"},
+		{Time: 1451368800, PrecipType: "rain"},
+		{Time: 1451372400, PrecipType: "sleet"},
+		{Time: 1451376000, PrecipType: "snow"},
+		{Time: 1451379600},
+	}}
+
+	expected := []PrecipTransition{
+		{Time: 1451372400, From: "rain", To: "sleet"},
+		{Time: 1451376000, From: "sleet", To: "snow"},
+		{Time: 1451379600, From: "snow", To: ""},
+	}
+
+	if transitions := db.PrecipTransitions(); !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("Expected %v, got %v.", expected, transitions)
+	}
+
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if transitions := forecast.Minutely.PrecipTransitions(); transitions != nil {
+		t.Errorf("Expected no transitions when the type never changes, got %v.", transitions)
+	}
+
+	var empty *DataBlock
+
+	if empty.PrecipTransitions() != nil || (&DataBlock{Data: db.Data[:1]}).PrecipTransitions() != nil {
+		t.Error("Expected nil for an empty or single-element block.")
+	}
+}
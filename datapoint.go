@@ -0,0 +1,206 @@
+package darksky
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// DayLength returns the time between SunriseTime and SunsetTime. Zero is returned when either
+// is missing, which happens for hourly data points and at high latitudes during polar day or night.
+func (dp DataPoint) DayLength() time.Duration {
+	if dp.SunriseTime == 0 || dp.SunsetTime == 0 {
+		return 0
+	}
+
+	return time.Duration(dp.SunsetTime-dp.SunriseTime) * time.Second
+}
+
+// SolarNoon returns the midpoint between SunriseTime and SunsetTime, an approximation of local
+// solar noon. It is the simple midpoint, not an astronomically precise value. The zero time.Time
+// is returned when either sunrise or sunset is missing.
+func (dp DataPoint) SolarNoon() time.Time {
+	if dp.SunriseTime == 0 || dp.SunsetTime == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(dp.SunriseTime+(dp.SunsetTime-dp.SunriseTime)/2, 0)
+}
+
+// DaylightProgress returns how far through the daylight period now is, from 0 at sunrise to 1 at
+// sunset. Times before sunrise are clamped to 0 and times after sunset to 1. -1 is returned when
+// either SunriseTime or SunsetTime is missing, such as during polar day or night.
+func (dp DataPoint) DaylightProgress(now time.Time) float64 {
+	if dp.SunriseTime == 0 || dp.SunsetTime == 0 || dp.SunsetTime <= dp.SunriseTime {
+		return -1
+	}
+
+	progress := float64(now.Unix()-dp.SunriseTime) / float64(dp.SunsetTime-dp.SunriseTime)
+
+	return math.Max(0, math.Min(1, progress))
+}
+
+// DewPointComfort describes how humid it feels based on DewPoint, which is assumed to be in
+// degrees Fahrenheit (US units). Use DewPointComfortCelsius for SI, CA, UK and UK2 units.
+//
+//	below 50°F        "dry"
+//	50°F up to 55°F   "comfortable"
+//	55°F up to 65°F   "humid"
+//	65°F and above    "oppressive"
+func (dp DataPoint) DewPointComfort() string {
+	return dewPointComfort(dp.DewPoint)
+}
+
+// DewPointComfortCelsius is DewPointComfort for a DewPoint in degrees Celsius.
+func (dp DataPoint) DewPointComfortCelsius() string {
+	return dewPointComfort(dp.DewPoint*9/5 + 32)
+}
+
+func dewPointComfort(fahrenheit float64) string {
+	switch {
+	case fahrenheit < 50:
+		return "dry"
+	case fahrenheit < 55:
+		return "comfortable"
+	case fahrenheit < 65:
+		return "humid"
+	default:
+		return "oppressive"
+	}
+}
+
+// CloudCoverDescription describes the sky based on the CloudCover fraction.
+//
+//	below 0.2          "clear"
+//	0.2 up to 0.6      "partly cloudy"
+//	0.6 up to 0.9      "mostly cloudy"
+//	0.9 and above      "overcast"
+func (dp DataPoint) CloudCoverDescription() string {
+	switch {
+	case dp.CloudCover < 0.2:
+		return "clear"
+	case dp.CloudCover < 0.6:
+		return "partly cloudy"
+	case dp.CloudCover < 0.9:
+		return "mostly cloudy"
+	default:
+		return "overcast"
+	}
+}
+
+// PrecipProbabilityPercent returns PrecipProbability as a percentage from 0 to 100, rounded to the
+// nearest whole number.
+func (dp DataPoint) PrecipProbabilityPercent() int {
+	return int(math.Floor(dp.PrecipProbability*100 + 0.5))
+}
+
+// PrecipProbabilityString returns PrecipProbability formatted as a percentage. (ex: 0.4 => "40%")
+func (dp DataPoint) PrecipProbabilityString() string {
+	return strconv.Itoa(dp.PrecipProbabilityPercent()) + "%"
+}
+
+// MoonIllumination approximates the illuminated fraction of the moon, from 0 to 1, using the
+// fractional lunation number in MoonPhase:
+//
+//	(1 - cos(2π × MoonPhase)) / 2
+//
+// A new moon (0) is 0, the first and last quarters (0.25, 0.75) are 0.5, and a full moon (0.5) is 1.
+func (dp DataPoint) MoonIllumination() float64 {
+	return (1 - math.Cos(2*math.Pi*dp.MoonPhase)) / 2
+}
+
+// inHgPerHPa is the number of inches of mercury in one hectopascal.
+const inHgPerHPa = 0.029529983071445
+
+// PressureHPa returns Pressure in hectopascals. The API reports pressure in millibars for US units
+// and hectopascals for every other units system, and the two are equal, so no conversion is
+// needed whatever units the forecast was requested in.
+func (dp DataPoint) PressureHPa() float64 {
+	return dp.Pressure
+}
+
+// PressureInHg returns Pressure in inches of mercury, as used for US aviation. As with PressureHPa
+// the source value is millibars or hectopascals regardless of the forecast's units.
+func (dp DataPoint) PressureInHg() float64 {
+	return dp.Pressure * inHgPerHPa
+}
+
+// OzoneDescription describes the columnar density of the ozone layer, which the API reports in
+// Dobson units. This is not an air quality index, it describes how much ozone is overhead to
+// filter UV radiation. The global average is roughly 300 DU.
+//
+//	below 220 DU       "low" (the threshold used to define the ozone hole)
+//	220 up to 400 DU   "normal"
+//	400 DU and above   "high"
+func (dp DataPoint) OzoneDescription() string {
+	switch {
+	case dp.Ozone < 220:
+		return "low"
+	case dp.Ozone < 400:
+		return "normal"
+	default:
+		return "high"
+	}
+}
+
+// High returns the daily high temperature. The API's TemperatureHigh is used when present, falling
+// back to the older TemperatureMax field for payloads that only contain it. Presence is judged by
+// the accompanying time field being set.
+func (dp DataPoint) High() float64 {
+	if dp.TemperatureHighTime != 0 {
+		return dp.TemperatureHigh
+	}
+
+	return dp.TemperatureMax
+}
+
+// Low returns the daily low temperature, preferring TemperatureLow over the older TemperatureMin.
+// See High for how presence is judged.
+func (dp DataPoint) Low() float64 {
+	if dp.TemperatureLowTime != 0 {
+		return dp.TemperatureLow
+	}
+
+	return dp.TemperatureMin
+}
+
+// ApparentHigh returns the daily high apparent temperature, preferring ApparentTemperatureHigh over
+// the older ApparentTemperatureMax. See High for how presence is judged.
+func (dp DataPoint) ApparentHigh() float64 {
+	if dp.ApparentTemperatureHighTime != 0 {
+		return dp.ApparentTemperatureHigh
+	}
+
+	return dp.ApparentTemperatureMax
+}
+
+// ApparentLow returns the daily low apparent temperature, preferring ApparentTemperatureLow over
+// the older ApparentTemperatureMin. See High for how presence is judged.
+func (dp DataPoint) ApparentLow() float64 {
+	if dp.ApparentTemperatureLowTime != 0 {
+		return dp.ApparentTemperatureLow
+	}
+
+	return dp.ApparentTemperatureMin
+}
+
+// normalizeTemperatures fills in whichever of the legacy min/max and newer high/low daily fields
+// the API left out, so code reading either set sees the same values regardless of payload age.
+// Fields already present in the payload are never overwritten.
+func normalizeTemperatures(dp *DataPoint) {
+	fill := func(v *float64, t *int64, fromV float64, fromT int64) {
+		if *t == 0 && fromT != 0 {
+			*v, *t = fromV, fromT
+		}
+	}
+
+	fill(&dp.TemperatureHigh, &dp.TemperatureHighTime, dp.TemperatureMax, dp.TemperatureMaxTime)
+	fill(&dp.TemperatureLow, &dp.TemperatureLowTime, dp.TemperatureMin, dp.TemperatureMinTime)
+	fill(&dp.TemperatureMax, &dp.TemperatureMaxTime, dp.TemperatureHigh, dp.TemperatureHighTime)
+	fill(&dp.TemperatureMin, &dp.TemperatureMinTime, dp.TemperatureLow, dp.TemperatureLowTime)
+
+	fill(&dp.ApparentTemperatureHigh, &dp.ApparentTemperatureHighTime, dp.ApparentTemperatureMax, dp.ApparentTemperatureMaxTime)
+	fill(&dp.ApparentTemperatureLow, &dp.ApparentTemperatureLowTime, dp.ApparentTemperatureMin, dp.ApparentTemperatureMinTime)
+	fill(&dp.ApparentTemperatureMax, &dp.ApparentTemperatureMaxTime, dp.ApparentTemperatureHigh, dp.ApparentTemperatureHighTime)
+	fill(&dp.ApparentTemperatureMin, &dp.ApparentTemperatureMinTime, dp.ApparentTemperatureLow, dp.ApparentTemperatureLowTime)
+}
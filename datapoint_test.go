@@ -0,0 +1,278 @@
+package darksky
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDataPoint_DayLength(t *testing.T) {
+	dp := DataPoint{SunriseTime: 1451396640, SunsetTime: 1451430480}
+
+	if dp.DayLength() != 9*time.Hour+24*time.Minute {
+		t.Errorf("Expected DayLength to be 9h24m, was %v.", dp.DayLength())
+	}
+
+	// Polar night, the sun never rises.
+	dp = DataPoint{SunsetTime: 1451430480}
+
+	if dp.DayLength() != 0 {
+		t.Errorf("Expected DayLength without a sunrise to be 0, was %v.", dp.DayLength())
+	}
+
+	// Polar day, the sun never sets.
+	dp = DataPoint{SunriseTime: 1451396640}
+
+	if dp.DayLength() != 0 {
+		t.Errorf("Expected DayLength without a sunset to be 0, was %v.", dp.DayLength())
+	}
+}
+
+func TestDataPoint_SolarNoon(t *testing.T) {
+	dp := DataPoint{SunriseTime: 1451396640, SunsetTime: 1451430480}
+
+	if dp.SolarNoon().Unix() != 1451413560 {
+		t.Errorf("Expected SolarNoon to be %v, was %v.", 1451413560, dp.SolarNoon().Unix())
+	}
+
+	dp.SunsetTime = 0
+
+	if !dp.SolarNoon().IsZero() {
+		t.Errorf("Expected SolarNoon without a sunset to be zero, was %v.", dp.SolarNoon())
+	}
+}
+
+func TestDataPoint_DaylightProgress(t *testing.T) {
+	dp := DataPoint{SunriseTime: 1451396640, SunsetTime: 1451430480}
+
+	tests := []struct {
+		now      int64
+		expected float64
+	}{
+		{1451390000, 0},
+		{1451396640, 0},
+		{1451413560, 0.5},
+		{1451430480, 1},
+		{1451440000, 1},
+	}
+
+	for _, test := range tests {
+		if progress := dp.DaylightProgress(time.Unix(test.now, 0)); progress != test.expected {
+			t.Errorf("Expected progress at %v to be %v, was %v.", test.now, test.expected, progress)
+		}
+	}
+
+	dp.SunriseTime = 0
+
+	if progress := dp.DaylightProgress(time.Unix(1451413560, 0)); progress != -1 {
+		t.Errorf("Expected progress without a sunrise to be -1, was %v.", progress)
+	}
+}
+
+func TestDataPoint_DewPointComfort(t *testing.T) {
+	tests := []struct {
+		dewPoint float64
+		expected string
+	}{
+		{-10, "dry"},
+		{49.9, "dry"},
+		{50, "comfortable"},
+		{54.9, "comfortable"},
+		{55, "humid"},
+		{64.9, "humid"},
+		{65, "oppressive"},
+		{80, "oppressive"},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{DewPoint: test.dewPoint}
+
+		if dp.DewPointComfort() != test.expected {
+			t.Errorf("Expected DewPoint of %v°F to be %v, was %v.", test.dewPoint, test.expected, dp.DewPointComfort())
+		}
+	}
+
+	celsius := []struct {
+		dewPoint float64
+		expected string
+	}{
+		{9.9, "dry"},
+		{10, "comfortable"},
+		{15, "humid"},
+		{18.4, "oppressive"},
+	}
+
+	for _, test := range celsius {
+		dp := DataPoint{DewPoint: test.dewPoint}
+
+		if dp.DewPointComfortCelsius() != test.expected {
+			t.Errorf("Expected DewPoint of %v°C to be %v, was %v.", test.dewPoint, test.expected, dp.DewPointComfortCelsius())
+		}
+	}
+}
+
+func TestDataPoint_CloudCoverDescription(t *testing.T) {
+	tests := []struct {
+		cloudCover float64
+		expected   string
+	}{
+		{0, "clear"},
+		{0.19, "clear"},
+		{0.2, "partly cloudy"},
+		{0.59, "partly cloudy"},
+		{0.6, "mostly cloudy"},
+		{0.89, "mostly cloudy"},
+		{0.9, "overcast"},
+		{1, "overcast"},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{CloudCover: test.cloudCover}
+
+		if dp.CloudCoverDescription() != test.expected {
+			t.Errorf("Expected CloudCover of %v to be %v, was %v.", test.cloudCover, test.expected, dp.CloudCoverDescription())
+		}
+	}
+}
+
+func TestDataPoint_PrecipProbabilityPercent(t *testing.T) {
+	tests := []struct {
+		probability float64
+		percent     int
+		str         string
+	}{
+		{0, 0, "0%"},
+		{0.04, 4, "4%"},
+		{0.29, 29, "29%"},
+		{0.4, 40, "40%"},
+		{0.645, 65, "65%"},
+		{1, 100, "100%"},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{PrecipProbability: test.probability}
+
+		if dp.PrecipProbabilityPercent() != test.percent {
+			t.Errorf("Expected %v to be %v percent, was %v.", test.probability, test.percent, dp.PrecipProbabilityPercent())
+		}
+
+		if dp.PrecipProbabilityString() != test.str {
+			t.Errorf("Expected %v to format as %v, was %v.", test.probability, test.str, dp.PrecipProbabilityString())
+		}
+	}
+}
+
+func TestDataPoint_MoonIllumination(t *testing.T) {
+	tests := []struct {
+		phase    float64
+		expected float64
+	}{
+		{0, 0},
+		{0.25, 0.5},
+		{0.5, 1},
+		{0.75, 0.5},
+		{1, 0},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{MoonPhase: test.phase}
+
+		if math.Abs(dp.MoonIllumination()-test.expected) > 1e-9 {
+			t.Errorf("Expected MoonPhase of %v to be %v illuminated, was %v.", test.phase, test.expected, dp.MoonIllumination())
+		}
+	}
+}
+
+func TestDataPoint_Pressure(t *testing.T) {
+	dp := DataPoint{Pressure: 1013.25}
+
+	if dp.PressureHPa() != 1013.25 {
+		t.Errorf("Expected PressureHPa to be 1013.25, was %v.", dp.PressureHPa())
+	}
+
+	// Standard atmospheric pressure is 29.92 inHg.
+	if math.Abs(dp.PressureInHg()-29.92) > 0.005 {
+		t.Errorf("Expected PressureInHg to be 29.92, was %v.", dp.PressureInHg())
+	}
+}
+
+func TestDataPoint_OzoneDescription(t *testing.T) {
+	tests := []struct {
+		ozone    float64
+		expected string
+	}{
+		{150, "low"},
+		{219.9, "low"},
+		{220, "normal"},
+		{294.07, "normal"},
+		{399.9, "normal"},
+		{400, "high"},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{Ozone: test.ozone}
+
+		if dp.OzoneDescription() != test.expected {
+			t.Errorf("Expected Ozone of %v to be %v, was %v.", test.ozone, test.expected, dp.OzoneDescription())
+		}
+	}
+}
+
+func TestDataPoint_HighLow(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	// The fixture only has the older min/max fields.
+	day := forecast.Daily.Data[0]
+
+	if day.High() != day.TemperatureMax || day.Low() != day.TemperatureMin {
+		t.Errorf("Expected High/Low to fall back to Max/Min, got %v/%v.", day.High(), day.Low())
+	}
+
+	if day.ApparentHigh() != day.ApparentTemperatureMax || day.ApparentLow() != day.ApparentTemperatureMin || day.ApparentHigh() == 0 {
+		t.Errorf("Expected ApparentHigh/ApparentLow to fall back to Max/Min, got %v/%v.", day.ApparentHigh(), day.ApparentLow())
+	}
+
+	day = DataPoint{
+		TemperatureMax: 40, TemperatureMin: 30,
+		TemperatureHigh: 38, TemperatureHighTime: 1451415600,
+		TemperatureLow: 0, TemperatureLowTime: 1451466000,
+		ApparentTemperatureMax: 35, ApparentTemperatureMin: 25,
+		ApparentTemperatureHigh: 33, ApparentTemperatureHighTime: 1451415600,
+		ApparentTemperatureLow: -5, ApparentTemperatureLowTime: 1451466000,
+	}
+
+	if day.High() != 38 || day.Low() != 0 {
+		t.Errorf("Expected High/Low to take precedence, got %v/%v.", day.High(), day.Low())
+	}
+
+	if day.ApparentHigh() != 33 || day.ApparentLow() != -5 {
+		t.Errorf("Expected ApparentHigh/ApparentLow to take precedence, got %v/%v.", day.ApparentHigh(), day.ApparentLow())
+	}
+}
+
+func TestDataPoint_NormalizeTemperatures(t *testing.T) {
+	legacy := loadForecast(t, "testdata/chicago_forecast.json").Daily.Data[0]
+
+	if legacy.TemperatureHigh != legacy.TemperatureMax || legacy.TemperatureHighTime != legacy.TemperatureMaxTime {
+		t.Errorf("Expected TemperatureHigh to be copied from TemperatureMax, got %v.", legacy.TemperatureHigh)
+	}
+
+	if legacy.TemperatureLow != legacy.TemperatureMin || legacy.ApparentTemperatureLow != legacy.ApparentTemperatureMin {
+		t.Errorf("Expected low fields to be copied from min fields, got %v/%v.", legacy.TemperatureLow, legacy.ApparentTemperatureLow)
+	}
+
+	modern := loadForecast(t, "testdata/modern_forecast.json").Daily.Data[0]
+
+	if modern.TemperatureMax != 36.5 || modern.TemperatureMaxTime != 1451426400 {
+		t.Errorf("Expected TemperatureMax to be copied from TemperatureHigh, got %v.", modern.TemperatureMax)
+	}
+
+	if modern.TemperatureMin != 27.1 || modern.ApparentTemperatureMax != 29.8 || modern.ApparentTemperatureMin != 18.4 {
+		t.Errorf("Expected legacy fields to be copied from high/low fields, got %v/%v/%v.",
+			modern.TemperatureMin, modern.ApparentTemperatureMax, modern.ApparentTemperatureMin)
+	}
+
+	if modern.High() != 36.5 || modern.Low() != 27.1 {
+		t.Errorf("Expected High/Low of 36.5/27.1, got %v/%v.", modern.High(), modern.Low())
+	}
+}
@@ -0,0 +1,89 @@
+package darksky
+
+import (
+	"math"
+	"strconv"
+)
+
+// ForecastDiff summarizes what changed between two forecasts for the same location, as returned by
+// Forecast.Diff. Deltas are the newer value minus the older, and are zero when either forecast is
+// missing its currently block.
+type ForecastDiff struct {
+	TemperatureDelta       float64
+	PrecipProbabilityDelta float64
+	IconChanged            bool
+	OldIcon                string
+	NewIcon                string
+	NewAlerts              []Alert
+	RemovedAlerts          []Alert
+}
+
+// Diff compares the forecast with a newer one for the same location, such as the result of the
+// next poll, returning the changes to the currently block and to the alerts. Alerts are matched as
+// in NewAlertsSince.
+func (f *Forecast) Diff(other Forecast) ForecastDiff {
+	var d ForecastDiff
+
+	if f.Currently != nil && other.Currently != nil {
+		d.TemperatureDelta = other.Currently.Temperature - f.Currently.Temperature
+		d.PrecipProbabilityDelta = other.Currently.PrecipProbability - f.Currently.PrecipProbability
+		d.OldIcon, d.NewIcon = f.Currently.Icon, other.Currently.Icon
+		d.IconChanged = d.OldIcon != d.NewIcon
+	}
+
+	d.NewAlerts = other.NewAlertsSince(f.Alerts)
+	d.RemovedAlerts = alertsMissingFrom(f.Alerts, other.Alerts)
+
+	return d
+}
+
+// NewAlertsSince returns the alerts in the forecast that aren't in previous, such as the alerts
+// from the last poll, so a notification can be sent exactly once per alert. Alerts are identified
+// by their URI, or by their title and expiry time together when they don't have a URI. Safe to call
+// on a nil Forecast.
+func (f *Forecast) NewAlertsSince(previous []Alert) []Alert {
+	if f == nil {
+		return nil
+	}
+
+	return alertsMissingFrom(f.Alerts, previous)
+}
+
+// Changed reports whether anything at all changed.
+func (d ForecastDiff) Changed() bool {
+	return d.Significant(0, 0)
+}
+
+// Significant reports whether the changes are worth notifying about: the icon changed, an alert
+// was added or removed, or the temperature or precipitation probability moved by more than the
+// given thresholds. The thresholds are in the forecast's temperature units and 0 to 1 probability.
+func (d ForecastDiff) Significant(temperature float64, precipProbability float64) bool {
+	return d.IconChanged || len(d.NewAlerts) > 0 || len(d.RemovedAlerts) > 0 ||
+		math.Abs(d.TemperatureDelta) > temperature || math.Abs(d.PrecipProbabilityDelta) > precipProbability
+}
+
+// alertsMissingFrom returns the alerts in alerts that aren't in other, matched by alertKey.
+func alertsMissingFrom(alerts []Alert, other []Alert) []Alert {
+	seen := map[string]bool{}
+	for _, a := range other {
+		seen[alertKey(a)] = true
+	}
+
+	var missing []Alert
+	for _, a := range alerts {
+		if !seen[alertKey(a)] {
+			missing = append(missing, a)
+		}
+	}
+
+	return missing
+}
+
+// alertKey identifies an alert across polls: its URI, or its title and expiry time when it has no URI.
+func alertKey(a Alert) string {
+	if a.URI != "" {
+		return a.URI
+	}
+
+	return a.Title + "\x00" + strconv.FormatInt(a.Expires, 10)
+}
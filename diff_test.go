@@ -0,0 +1,88 @@
+package darksky
+
+import "testing"
+
+func TestForecast_Diff(t *testing.T) {
+	old := loadForecast(t, "testdata/chicago_forecast.json")
+	current := loadForecast(t, "testdata/chicago_forecast.json")
+
+	d := old.Diff(*current)
+	if d.Changed() {
+		t.Errorf("Expected no changes between identical forecasts, got %+v.", d)
+	}
+
+	current.Currently.Temperature += 2.5
+	current.Currently.PrecipProbability += 0.3
+	current.Currently.Icon = "rain"
+	current.Alerts = append(current.Alerts[1:], Alert{Title: "Wind Advisory", Expires: 1451433600})
+
+	d = old.Diff(*current)
+
+	if !closeTo(d.TemperatureDelta, 2.5) || !closeTo(d.PrecipProbabilityDelta, 0.3) {
+		t.Errorf("Expected deltas of 2.5 and 0.3, got %v and %v.", d.TemperatureDelta, d.PrecipProbabilityDelta)
+	}
+
+	if !d.IconChanged || d.OldIcon != old.Currently.Icon || d.NewIcon != "rain" {
+		t.Errorf("Expected the icon change to be reported, got %+v.", d)
+	}
+
+	if len(d.NewAlerts) != 1 || d.NewAlerts[0].Title != "Wind Advisory" {
+		t.Errorf("Expected the new alert to be reported, got %v.", d.NewAlerts)
+	}
+
+	if len(d.RemovedAlerts) != 1 || d.RemovedAlerts[0].URI != old.Alerts[0].URI {
+		t.Errorf("Expected the removed alert to be reported, got %v.", d.RemovedAlerts)
+	}
+}
+
+func TestForecastDiff_Significant(t *testing.T) {
+	tests := []struct {
+		diff     ForecastDiff
+		expected bool
+	}{
+		{ForecastDiff{}, false},
+		{ForecastDiff{TemperatureDelta: -1.5}, false},
+		{ForecastDiff{TemperatureDelta: -3}, true},
+		{ForecastDiff{PrecipProbabilityDelta: 0.1}, false},
+		{ForecastDiff{PrecipProbabilityDelta: 0.25}, true},
+		{ForecastDiff{IconChanged: true}, true},
+		{ForecastDiff{NewAlerts: []Alert{{Title: "Flood Watch"}}}, true},
+		{ForecastDiff{RemovedAlerts: []Alert{{Title: "Flood Watch"}}}, true},
+	}
+
+	for _, test := range tests {
+		if test.diff.Significant(2, 0.2) != test.expected {
+			t.Errorf("Expected %+v to be significant: %v.", test.diff, test.expected)
+		}
+	}
+}
+
+func TestForecast_NewAlertsSince(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if alerts := forecast.NewAlertsSince(forecast.Alerts); len(alerts) != 0 {
+		t.Errorf("Expected no new alerts, got %v.", alerts)
+	}
+
+	if alerts := forecast.NewAlertsSince(nil); len(alerts) != 3 {
+		t.Errorf("Expected every alert to be new without a previous poll, got %v.", len(alerts))
+	}
+
+	previous := append([]Alert{}, forecast.Alerts[:2]...)
+	previous[0].Description = "Updated description."
+
+	if alerts := forecast.NewAlertsSince(previous); len(alerts) != 1 || alerts[0].URI != forecast.Alerts[2].URI {
+		t.Errorf("Expected only the third alert to be new, got %v.", alerts)
+	}
+
+	// Without a URI, alerts are identified by title and expiry time.
+	forecast.Alerts = []Alert{{Title: "Wind Advisory", Expires: 1451433600}, {Title: "Wind Advisory", Expires: 1451440800}}
+
+	if alerts := forecast.NewAlertsSince([]Alert{{Title: "Wind Advisory", Expires: 1451433600}}); len(alerts) != 1 || alerts[0].Expires != 1451440800 {
+		t.Errorf("Expected the extended advisory to be new, got %v.", alerts)
+	}
+
+	if alerts := (*Forecast)(nil).NewAlertsSince(nil); alerts != nil {
+		t.Errorf("Expected no alerts for a nil forecast, got %v.", alerts)
+	}
+}
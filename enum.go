@@ -0,0 +1,103 @@
+package darksky
+
+import "fmt"
+
+// units is every known Units value.
+var units = []Units{US, SI, CA, UK, UK2, AUTO}
+
+// langs is every known Lang value.
+var langs = []Lang{
+	Arabic, Bosnian, German, Greek, English, Spanish, French, Croatian, Italian, Dutch, Polish,
+	Portuguese, Russian, Slovak, Swedish, Tetum, Turkish, Ukranian, PigLatin, Chinese, TraditionalChinese,
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u Units) MarshalText() ([]byte, error) {
+	return []byte(u), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, returning an error for unknown units, so
+// Units can be loaded from configuration files with validation.
+func (u *Units) UnmarshalText(text []byte) error {
+	for _, known := range units {
+		if string(known) == string(text) {
+			*u = known
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown units %q", text)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Lang) MarshalText() ([]byte, error) {
+	return []byte(l), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, returning an error for unknown languages, so
+// Lang can be loaded from configuration files with validation.
+func (l *Lang) UnmarshalText(text []byte) error {
+	for _, known := range langs {
+		if string(known) == string(text) {
+			*l = known
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown language %q", text)
+}
+
+// unitsNames are the human friendly names returned by Units.DisplayName.
+var unitsNames = map[Units]string{
+	US:   "US (imperial)",
+	SI:   "SI (metric)",
+	CA:   "Canadian (metric, km/h)",
+	UK:   "UK (metric, mph, km)",
+	UK2:  "UK (metric, mph, miles)",
+	AUTO: "Automatic (based on location)",
+}
+
+// langNames are the human friendly names returned by Lang.DisplayName.
+var langNames = map[Lang]string{
+	Arabic:             "Arabic",
+	Bosnian:            "Bosnian",
+	German:             "German",
+	Greek:              "Greek",
+	English:            "English",
+	Spanish:            "Spanish",
+	French:             "French",
+	Croatian:           "Croatian",
+	Italian:            "Italian",
+	Dutch:              "Dutch",
+	Polish:             "Polish",
+	Portuguese:         "Portuguese",
+	Russian:            "Russian",
+	Slovak:             "Slovak",
+	Swedish:            "Swedish",
+	Tetum:              "Tetum",
+	Turkish:            "Turkish",
+	Ukranian:           "Ukrainian",
+	PigLatin:           "Igpay Atinlay",
+	Chinese:            "Simplified Chinese",
+	TraditionalChinese: "Traditional Chinese",
+}
+
+// DisplayName returns a human friendly name for the units, such as "SI (metric)", for use in help
+// text and settings screens. Unknown units return their code.
+func (u Units) DisplayName() string {
+	if name, ok := unitsNames[u]; ok {
+		return name
+	}
+
+	return string(u)
+}
+
+// DisplayName returns the English name of the language, such as "Traditional Chinese" for zh-tw.
+// Unknown languages return their code.
+func (l Lang) DisplayName() string {
+	if name, ok := langNames[l]; ok {
+		return name
+	}
+
+	return string(l)
+}
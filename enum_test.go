@@ -0,0 +1,96 @@
+package darksky
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnits_UnmarshalText(t *testing.T) {
+	var config struct {
+		Units Units `json:"units"`
+		Lang  Lang  `json:"lang"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"units":"si","lang":"zh-tw"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Units != SI || config.Lang != TraditionalChinese {
+		t.Errorf("Expected si and zh-tw, got %v and %v.", config.Units, config.Lang)
+	}
+
+	for _, data := range []string{`{"units":"metric"}`, `{"units":"SI"}`, `{"lang":"klingon"}`} {
+		if err := json.Unmarshal([]byte(data), &config); err == nil {
+			t.Errorf("Expected %v to be invalid.", data)
+		}
+	}
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != `{"units":"si","lang":"zh-tw"}` {
+		t.Errorf("Expected the codes to round trip, got %s.", out)
+	}
+}
+
+func TestUnits_TextRoundTrip(t *testing.T) {
+	for _, u := range units {
+		text, _ := u.MarshalText()
+
+		var parsed Units
+		if err := parsed.UnmarshalText(text); err != nil || parsed != u {
+			t.Errorf("Expected %v to round trip, got %v, %v.", u, parsed, err)
+		}
+	}
+
+	for _, l := range langs {
+		text, _ := l.MarshalText()
+
+		var parsed Lang
+		if err := parsed.UnmarshalText(text); err != nil || parsed != l {
+			t.Errorf("Expected %v to round trip, got %v, %v.", l, parsed, err)
+		}
+	}
+}
+
+func TestUnits_DisplayName(t *testing.T) {
+	seen := map[string]bool{}
+
+	for _, u := range units {
+		name, ok := unitsNames[u]
+		if !ok {
+			t.Errorf("Expected a display name for %v.", u)
+		}
+
+		if seen[name] {
+			t.Errorf("Expected a unique display name for %v, %q is used more than once.", u, name)
+		}
+
+		seen[name] = true
+	}
+
+	seen = map[string]bool{}
+
+	for _, l := range langs {
+		name, ok := langNames[l]
+		if !ok {
+			t.Errorf("Expected a display name for %v.", l)
+		}
+
+		if seen[name] {
+			t.Errorf("Expected a unique display name for %v, %q is used more than once.", l, name)
+		}
+
+		seen[name] = true
+	}
+
+	if SI.DisplayName() != "SI (metric)" || TraditionalChinese.DisplayName() != "Traditional Chinese" {
+		t.Errorf("Unexpected display names %q and %q.", SI.DisplayName(), TraditionalChinese.DisplayName())
+	}
+
+	if Units("xx").DisplayName() != "xx" || Lang("xx").DisplayName() != "xx" {
+		t.Error("Expected unknown codes to be returned as-is.")
+	}
+}
@@ -0,0 +1,69 @@
+package darksky
+
+import "encoding/json"
+
+// NetworkError is returned when the API couldn't be reached or the response couldn't be read, such
+// as a DNS failure, refused connection or timeout. These are usually worth retrying.
+type NetworkError struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a successful response couldn't be parsed as a forecast, including
+// when it wasn't JSON at all.
+type DecodeError struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (e *DecodeError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is an error response from the Dark Sky API. Error bodies in the API's JSON format, such
+// as {"code":400,"error":"The given location is invalid."}, are parsed into Code and Message.
+// Otherwise Code is the HTTP status code and Message is the body as text.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"error"`
+}
+
+// Error returns the Message of the APIError.
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// newAPIError builds an APIError from an error response.
+func newAPIError(status int, contentType string, body []byte) *APIError {
+	if isHTML(contentType, body) {
+		return &APIError{Code: status, Message: nonJSONError(body).Error()}
+	}
+
+	if isJSON(contentType, body) {
+		var e APIError
+
+		if err := json.Unmarshal(body, &e); err == nil && e.Message != "" {
+			if e.Code == 0 {
+				e.Code = status
+			}
+
+			return &e
+		}
+	}
+
+	return &APIError{Code: status, Message: string(body)}
+}
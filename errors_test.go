@@ -0,0 +1,83 @@
+package darksky
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForecastRequest_Get_APIError(t *testing.T) {
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Content-Type", "application/json")
+		resp.WriteHeader(400)
+		resp.Write([]byte(`{"code":400,"error":"The given location is invalid."}`))
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		apiErr, ok := resp.Error.(*APIError)
+		if !ok {
+			t.Fatalf("Expected an *APIError, got %T.", resp.Error)
+		}
+
+		if apiErr.Code != 400 || apiErr.Message != "The given location is invalid." {
+			t.Errorf("Unexpected APIError: %+v", apiErr)
+		}
+
+		if apiErr.Error() != "The given location is invalid." {
+			t.Errorf("Unexpected Error(): %v", apiErr.Error())
+		}
+	})
+
+	usingTestServer(errorForecastHandler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+		apiErr, ok := resp.Error.(*APIError)
+		if !ok {
+			t.Fatalf("Expected an *APIError, got %T.", resp.Error)
+		}
+
+		if apiErr.Code != 500 || apiErr.Message != "A Server Error Occurred." {
+			t.Errorf("Expected a text body to fall back to the status code and raw text: %+v", apiErr)
+		}
+	})
+}
+
+func TestForecastRequest_Get_NetworkError(t *testing.T) {
+	ts := httptest.NewServer(validForecastHandler)
+	ts.Close()
+
+	resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(ts.URL).Get()
+
+	var netErr *NetworkError
+	if !errors.As(resp.Error, &netErr) {
+		t.Fatalf("Expected a *NetworkError, got %T.", resp.Error)
+	}
+
+	if errors.Unwrap(resp.Error) == nil {
+		t.Error("Expected the underlying error to be wrapped.")
+	}
+}
+
+func TestForecastRequest_Get_DecodeError(t *testing.T) {
+	for _, body := range []string{`{"currently": {"time": "not a number"}}`, `<html>Maintenance</html>`} {
+		handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			resp.Write([]byte(body))
+		})
+
+		usingTestServer(handler, func(testURL string) {
+			resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+
+			var decodeErr *DecodeError
+			if !errors.As(resp.Error, &decodeErr) {
+				t.Fatalf("Expected a *DecodeError for %q, got %T.", body, resp.Error)
+			}
+
+			if errors.Unwrap(resp.Error) == nil {
+				t.Error("Expected the underlying error to be wrapped.")
+			}
+		})
+	}
+}
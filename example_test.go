@@ -8,6 +8,17 @@ import (
 func ExampleGet() {
 	r := MakeRequest("my_key", 41.8781, -87.6297).Get()
 
+	if r.Error != nil {
+		fmt.Println(r.Error)
+		return
+	}
+
+	// Currently is nil when the block was excluded or omitted by the API.
+	if r.Forecast.Currently == nil {
+		fmt.Println("No current conditions.")
+		return
+	}
+
 	feelsLike := r.Forecast.Currently.ApparentTemperature
 
 	// All time fields are represented as seconds since epoch, so
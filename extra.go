@@ -0,0 +1,107 @@
+package darksky
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// rawBlock is a DataBlock with its data points left as raw JSON, used by collectExtra.
+type rawBlock struct {
+	Data []json.RawMessage `json:"data"`
+}
+
+// collectExtra fills in Extra for f and each of its data points from data, the JSON f was decoded
+// from. Each object is decoded a second time into a map, which is why it's only done when asked for
+// with WithExtraFields.
+func collectExtra(f *Forecast, data []byte) error {
+	var raw struct {
+		Currently json.RawMessage `json:"currently"`
+		Minutely  *rawBlock       `json:"minutely"`
+		Hourly    *rawBlock       `json:"hourly"`
+		Daily     *rawBlock       `json:"daily"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, reflect.TypeOf(*f))
+	if err != nil {
+		return err
+	}
+
+	f.Extra = extra
+
+	if f.Currently != nil && raw.Currently != nil {
+		if f.Currently.Extra, err = unknownFields(raw.Currently, reflect.TypeOf(DataPoint{})); err != nil {
+			return err
+		}
+	}
+
+	blocks := []struct {
+		db  *DataBlock
+		raw *rawBlock
+	}{{f.Minutely, raw.Minutely}, {f.Hourly, raw.Hourly}, {f.Daily, raw.Daily}}
+
+	for _, b := range blocks {
+		if b.db == nil || b.raw == nil {
+			continue
+		}
+
+		for i := range b.db.Data {
+			if i >= len(b.raw.Data) {
+				break
+			}
+
+			if b.db.Data[i].Extra, err = unknownFields(b.raw.Data[i], reflect.TypeOf(DataPoint{})); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// knownFields caches the set of JSON field names for each struct type passed to unknownFields.
+var knownFields sync.Map
+
+// unknownFields returns the fields of the JSON object in data that don't match a field of the
+// struct type t, or nil if there are none.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known, ok := knownFields.Load(t)
+	if !ok {
+		known, _ = knownFields.LoadOrStore(t, jsonFieldNames(t))
+	}
+
+	for name := range known.(map[string]bool) {
+		delete(fields, name)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return fields, nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
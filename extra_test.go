@@ -0,0 +1,83 @@
+package darksky
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestForecastRequest_WithExtraFields(t *testing.T) {
+	usingTestServer(validForecastHandler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).Get()
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if resp.Forecast.Currently.Extra != nil {
+			t.Errorf("Expected Extra not to be collected by default, got %v.", resp.Forecast.Currently.Extra)
+		}
+
+		resp = MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithExtraFields(true).Get()
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		forecast := resp.Forecast
+
+		if forecast.Extra != nil {
+			t.Errorf("Expected no unknown top level fields, got %v.", forecast.Extra)
+		}
+
+		if string(forecast.Currently.Extra["nearestStormDistance"]) != "0" {
+			t.Errorf("Expected nearestStormDistance to be kept in Extra, got %v.", forecast.Currently.Extra)
+		}
+
+		if _, ok := forecast.Currently.Extra["temperature"]; ok {
+			t.Error("Expected modelled fields to be left out of Extra.")
+		}
+	})
+}
+
+func TestForecast_Extra(t *testing.T) {
+	body := `{"timezone":"America/Chicago","elevation":181,"currently":{"time":1451362625,"smoke":{"level":2}},` +
+		`"hourly":{"data":[{"time":1451361600},{"time":1451365200,"pollen":"high"}]}}`
+
+	if forecast, err := Unmarshal([]byte(body)); err != nil || forecast.Extra != nil {
+		t.Errorf("Expected Unmarshal not to fill in Extra, got %v, %v.", forecast, err)
+	}
+
+	parsed, err := ParseForecastWithExtra(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(parsed.Extra["elevation"]) != "181" || string(parsed.Hourly.Data[1].Extra["pollen"]) != `"high"` {
+		t.Errorf("Expected ParseForecastWithExtra to fill in Extra, got %v and %v.", parsed.Extra, parsed.Hourly.Data[1].Extra)
+	}
+
+	forecast, err := UnmarshalWithExtra([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(forecast.Extra["elevation"]) != "181" || len(forecast.Extra) != 1 {
+		t.Errorf("Expected only elevation in Extra, got %v.", forecast.Extra)
+	}
+
+	if string(forecast.Currently.Extra["smoke"]) != `{"level":2}` || forecast.Currently.Time != 1451362625 {
+		t.Errorf("Expected smoke to be kept as raw JSON, got %v.", forecast.Currently.Extra)
+	}
+
+	if forecast.Hourly.Data[0].Extra != nil || string(forecast.Hourly.Data[1].Extra["pollen"]) != `"high"` {
+		t.Errorf("Expected pollen in the second hourly point's Extra only, got %v, %v.", forecast.Hourly.Data[0].Extra, forecast.Hourly.Data[1].Extra)
+	}
+
+	data, err := json.Marshal(forecast)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "elevation") {
+		t.Errorf("Expected Extra not to be marshalled, got %s.", data)
+	}
+}
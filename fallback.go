@@ -0,0 +1,97 @@
+package darksky
+
+import "context"
+
+// WithLangFallback makes Get request the forecast a second time in the fallback language when any
+// summary in the response is blank, as happens for some conditions in some languages, and fill in
+// the blank summaries from it. This costs an extra API call whenever a blank summary is found. If
+// the second request fails the original response is returned unchanged. An empty fallback
+// disables it.
+func (f *ForecastRequest) WithLangFallback(fallback Lang) *ForecastRequest {
+	f.langFallback = fallback
+	return f
+}
+
+// applyLangFallback requests the forecast in the fallback language and merges its summaries into
+// the blank summaries of fr.
+func (f *ForecastRequest) applyLangFallback(ctx context.Context, fr *ForecastResponse) {
+	r := f.Clone().WithLang(f.langFallback)
+
+	fallback := r.get(ctx)
+	if fallback.Error != nil {
+		return
+	}
+
+	if fallback.CallCountKnown {
+		fr.APICallCount = fallback.APICallCount
+		fr.CallCountKnown = true
+	}
+
+	// The forecast may be shared with a cache, so merge into a copy.
+	forecast := fr.Forecast.clone()
+
+	if forecast.Currently != nil && fallback.Forecast.Currently != nil {
+		mergeSummary(forecast.Currently, *fallback.Forecast.Currently)
+	}
+
+	mergeBlockSummaries(forecast.Minutely, fallback.Forecast.Minutely)
+	mergeBlockSummaries(forecast.Hourly, fallback.Forecast.Hourly)
+	mergeBlockSummaries(forecast.Daily, fallback.Forecast.Daily)
+
+	fr.Forecast = forecast
+}
+
+// mergeBlockSummaries fills in the blank summaries of db, and its data points, from fallback.
+// Data points are only merged when they are for the same time.
+func mergeBlockSummaries(db *DataBlock, fallback *DataBlock) {
+	if db == nil || fallback == nil {
+		return
+	}
+
+	if db.Summary == "" {
+		db.Summary = fallback.Summary
+	}
+
+	for i := range db.Data {
+		if i < len(fallback.Data) {
+			mergeSummary(&db.Data[i], fallback.Data[i])
+		}
+	}
+}
+
+// mergeSummary fills in the summary of dp from fallback when it's blank and both are for the same time.
+func mergeSummary(dp *DataPoint, fallback DataPoint) {
+	if dp.Summary == "" && dp.Time == fallback.Time {
+		dp.Summary = fallback.Summary
+	}
+}
+
+// hasBlankSummary reports whether the forecast, any of its blocks, or any of their data points has
+// a blank summary. Minutely data points never have a summary, so only the block's is checked.
+func hasBlankSummary(f *Forecast) bool {
+	if f.Currently != nil && f.Currently.Summary == "" {
+		return true
+	}
+
+	if f.Minutely != nil && f.Minutely.Summary == "" {
+		return true
+	}
+
+	for _, db := range []*DataBlock{f.Hourly, f.Daily} {
+		if db == nil {
+			continue
+		}
+
+		if db.Summary == "" {
+			return true
+		}
+
+		for _, dp := range db.Data {
+			if dp.Summary == "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
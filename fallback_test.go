@@ -0,0 +1,68 @@
+package darksky
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestForecastRequest_WithLangFallback(t *testing.T) {
+	jsonBytes, err := ioutil.ReadFile("testdata/chicago_forecast.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var blank map[string]interface{}
+	json.Unmarshal(jsonBytes, &blank)
+
+	blank["currently"].(map[string]interface{})["summary"] = ""
+	blank["hourly"].(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})["summary"] = ""
+	blankBytes, _ := json.Marshal(blank)
+
+	var langs []string
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		lang := req.URL.Query().Get("lang")
+		langs = append(langs, lang)
+
+		if lang == "de" {
+			resp.Write(blankBytes)
+		} else {
+			resp.Write(jsonBytes)
+		}
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		resp := MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithLang(German).Get()
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if resp.Forecast.Currently.Summary != "" || len(langs) != 1 {
+			t.Errorf("Expected no fallback by default, got %v requests.", len(langs))
+		}
+
+		langs = nil
+
+		resp = MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithLang(German).WithLangFallback(English).Get()
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if len(langs) != 2 || langs[1] != "en" {
+			t.Errorf("Expected a second request in English, got %v.", langs)
+		}
+
+		if resp.Forecast.Currently.Summary != "Mostly Cloudy" || resp.Forecast.Hourly.Data[0].Summary == "" {
+			t.Errorf("Expected blank summaries to be filled in, got %q.", resp.Forecast.Currently.Summary)
+		}
+
+		langs = nil
+
+		resp = MakeRequest(key, 41.8781, -87.6297).WithBaseURL(testURL).WithLangFallback(German).Get()
+		if resp.Error != nil || len(langs) != 1 {
+			t.Errorf("Expected no fallback without blank summaries, got %v requests.", len(langs))
+		}
+	})
+}
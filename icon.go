@@ -0,0 +1,123 @@
+package darksky
+
+import (
+	"strings"
+	"time"
+)
+
+// Icon is a machine readable summary of the weather, suitable for selecting an icon to display.
+type Icon string
+
+const (
+	ClearDayIcon          Icon = "clear-day"
+	ClearNightIcon        Icon = "clear-night"
+	RainIcon              Icon = "rain"
+	SnowIcon              Icon = "snow"
+	SleetIcon             Icon = "sleet"
+	WindIcon              Icon = "wind"
+	FogIcon               Icon = "fog"
+	CloudyIcon            Icon = "cloudy"
+	PartlyCloudyDayIcon   Icon = "partly-cloudy-day"
+	PartlyCloudyNightIcon Icon = "partly-cloudy-night"
+)
+
+// ambiguousIcons are generic icons that have both a day and a night variant.
+var ambiguousIcons = map[Icon]bool{"clear": true, "partly-cloudy": true}
+
+// IsDaytime reports whether the data point's Time is between SunriseTime and SunsetTime. Only
+// daily data points have sunrise and sunset times; for other points an Icon ending in "-night"
+// is taken as night time, and anything else as daytime. Use Forecast.IsDaytime for hourly and
+// currently data points.
+func (dp DataPoint) IsDaytime() bool {
+	if dp.hasSunTimes() {
+		return dp.Time >= dp.SunriseTime && dp.Time < dp.SunsetTime
+	}
+
+	return !strings.HasSuffix(dp.Icon, "-night")
+}
+
+func (dp DataPoint) hasSunTimes() bool {
+	return dp.SunriseTime != 0 && dp.SunsetTime != 0
+}
+
+// ResolvedIcon returns the data point's Icon, with "-day" or "-night" appended to generic icons
+// that have both variants, such as "clear", based on its SunriseTime and SunsetTime. All other
+// icons, including ones that are already day or night specific, are returned unchanged, as are
+// generic icons of data points without sunrise and sunset times. Use Forecast.ResolvedIcon for
+// hourly and currently data points.
+func (dp DataPoint) ResolvedIcon() Icon {
+	icon := Icon(dp.Icon)
+
+	if !ambiguousIcons[icon] || !dp.hasSunTimes() {
+		return icon
+	}
+
+	if dp.IsDaytime() {
+		return icon + "-day"
+	}
+
+	return icon + "-night"
+}
+
+// IsDaytime is DataPoint.IsDaytime for a data point of the forecast, such as an hourly one, using
+// the sunrise and sunset times of the daily data point for the same day when dp has none of its own.
+// Safe to call on a nil Forecast.
+func (f *Forecast) IsDaytime(dp DataPoint) bool {
+	return f.withSunTimes(dp).IsDaytime()
+}
+
+// ResolvedIcon is DataPoint.ResolvedIcon for a data point of the forecast, such as an hourly one,
+// using the sunrise and sunset times of the daily data point for the same day when dp has none of
+// its own. Generic icons are returned unchanged when the daily block has no point for that day.
+// Safe to call on a nil Forecast.
+func (f *Forecast) ResolvedIcon(dp DataPoint) Icon {
+	return f.withSunTimes(dp).ResolvedIcon()
+}
+
+// withSunTimes returns dp with the sunrise and sunset times of the daily data point for its day,
+// unless it already has them.
+func (f *Forecast) withSunTimes(dp DataPoint) DataPoint {
+	if dp.hasSunTimes() {
+		return dp
+	}
+
+	if day, ok := f.dailyPoint(time.Unix(dp.Time, 0)); ok {
+		dp.SunriseTime, dp.SunsetTime = day.SunriseTime, day.SunsetTime
+	}
+
+	return dp
+}
+
+// icons is every known Icon value.
+var icons = []Icon{
+	ClearDayIcon, ClearNightIcon, RainIcon, SnowIcon, SleetIcon, WindIcon, FogIcon, CloudyIcon,
+	PartlyCloudyDayIcon, PartlyCloudyNightIcon,
+}
+
+// iconEmoji maps each Icon to the emoji returned by Emoji.
+var iconEmoji = map[Icon]string{
+	ClearDayIcon:          "☀️",
+	ClearNightIcon:        "🌙",
+	RainIcon:              "🌧️",
+	SnowIcon:              "❄️",
+	SleetIcon:             "🌨️",
+	WindIcon:              "💨",
+	FogIcon:               "🌫️",
+	CloudyIcon:            "☁️",
+	PartlyCloudyDayIcon:   "⛅",
+	PartlyCloudyNightIcon: "☁️🌙",
+}
+
+// defaultEmoji is returned by Emoji for unknown icons.
+const defaultEmoji = "🌡️"
+
+// Emoji returns an emoji for the data point's icon, such as "🌧️" for rain, for use in chat messages.
+// Generic icons are first resolved to their day or night variant with ResolvedIcon. A thermometer,
+// "🌡️", is returned for unknown icons, and for generic icons that can't be resolved.
+func (dp DataPoint) Emoji() string {
+	if e, ok := iconEmoji[dp.ResolvedIcon()]; ok {
+		return e
+	}
+
+	return defaultEmoji
+}
@@ -0,0 +1,113 @@
+package darksky
+
+import "testing"
+
+func TestDataPoint_ResolvedIcon(t *testing.T) {
+	day := DataPoint{SunriseTime: 1451308800, SunsetTime: 1451342400}
+
+	tests := []struct {
+		icon     string
+		time     int64
+		expected Icon
+	}{
+		{"clear", 1451325600, ClearDayIcon},
+		{"clear", 1451350000, ClearNightIcon},
+		{"clear", 1451300000, ClearNightIcon},
+		{"partly-cloudy", 1451325600, PartlyCloudyDayIcon},
+		{"partly-cloudy", 1451350000, PartlyCloudyNightIcon},
+		{"clear-night", 1451325600, ClearNightIcon},
+		{"rain", 1451350000, RainIcon},
+	}
+
+	for _, test := range tests {
+		dp := day
+		dp.Icon, dp.Time = test.icon, test.time
+
+		if dp.ResolvedIcon() != test.expected {
+			t.Errorf("Expected %v at %v to resolve to %v, was %v.", test.icon, test.time, test.expected, dp.ResolvedIcon())
+		}
+	}
+}
+
+func TestDataPoint_IsDaytime(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	if forecast.Daily.Data[0].IsDaytime() {
+		t.Error("Expected midnight, before sunrise, to be night time.")
+	}
+
+	if (DataPoint{Icon: "partly-cloudy-night"}).IsDaytime() || !(DataPoint{Icon: "rain"}).IsDaytime() {
+		t.Error("Expected the icon to be used without sunrise and sunset times.")
+	}
+}
+
+func TestForecast_ResolvedIcon(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	// 10pm on Dec 28 and noon on Dec 29 in Chicago. Hourly points have no sunrise or sunset times.
+	night, noon := forecast.Hourly.Data[0], forecast.Hourly.Data[14]
+	night.Icon, noon.Icon = "clear", "partly-cloudy"
+
+	if icon := night.ResolvedIcon(); icon != "clear" {
+		t.Errorf("Expected a generic icon to be unchanged without sunrise and sunset, was %v.", icon)
+	}
+
+	if icon := forecast.ResolvedIcon(night); icon != ClearNightIcon {
+		t.Errorf("Expected an hourly point at night to resolve to %v, was %v.", ClearNightIcon, icon)
+	}
+
+	if icon := forecast.ResolvedIcon(noon); icon != PartlyCloudyDayIcon {
+		t.Errorf("Expected an hourly point at noon to resolve to %v, was %v.", PartlyCloudyDayIcon, icon)
+	}
+
+	if forecast.IsDaytime(night) || !forecast.IsDaytime(noon) {
+		t.Error("Expected the daily sunrise and sunset times to be used for hourly points.")
+	}
+
+	late := night
+	late.Time += 30 * 24 * 60 * 60
+
+	if icon := forecast.ResolvedIcon(late); icon != "clear" {
+		t.Errorf("Expected a generic icon to be unchanged outside of the daily block, was %v.", icon)
+	}
+
+	if icon := (*Forecast)(nil).ResolvedIcon(night); icon != "clear" {
+		t.Errorf("Expected a generic icon to be unchanged for a nil forecast, was %v.", icon)
+	}
+}
+
+func TestDataPoint_Emoji(t *testing.T) {
+	seen := map[string]Icon{}
+
+	for _, icon := range icons {
+		e, ok := iconEmoji[icon]
+		if !ok {
+			t.Errorf("Expected an emoji for %v.", icon)
+			continue
+		}
+
+		if other, ok := seen[e]; ok {
+			t.Errorf("Expected a distinct emoji for %v, %v is also %v.", icon, other, e)
+		}
+
+		seen[e] = icon
+	}
+
+	tests := []struct {
+		dp       DataPoint
+		expected string
+	}{
+		{DataPoint{Icon: "clear-day"}, "☀️"},
+		{DataPoint{Icon: "rain"}, "🌧️"},
+		{DataPoint{Icon: "snow"}, "❄️"},
+		{DataPoint{Icon: "clear", Time: 1451350000, SunriseTime: 1451308800, SunsetTime: 1451342400}, "🌙"},
+		{DataPoint{Icon: "tornado"}, "🌡️"},
+		{DataPoint{}, "🌡️"},
+	}
+
+	for _, test := range tests {
+		if test.dp.Emoji() != test.expected {
+			t.Errorf("Expected %q to be %v, was %v.", test.dp.Icon, test.expected, test.dp.Emoji())
+		}
+	}
+}
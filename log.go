@@ -0,0 +1,49 @@
+package darksky
+
+import (
+	"net/url"
+	"time"
+)
+
+// CorrelationIDHeader is the HTTP header WithCorrelationID sends the correlation ID in.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// RequestLog describes a single call to the API, as passed to the logger set with Client.WithLogger.
+// URL has the API key removed. StatusCode is 0 and Error set when no response was received.
+type RequestLog struct {
+	URL           string
+	CorrelationID string
+	StatusCode    int
+	APICallCount  int
+	Duration      time.Duration
+	Error         error
+}
+
+// WithLogger makes the Client call logger after every call to the API made by its requests,
+// whether it succeeded or not. Forecasts served from a Cache, and dry runs, aren't logged. The
+// logger may be called from multiple goroutines at once.
+func (c *Client) WithLogger(logger func(RequestLog)) *Client {
+	c.logger = logger
+	return c
+}
+
+// log passes the outcome of a call to the API for the request r to the Client's logger, if it has
+// one. Safe to call on a nil Client.
+func (c *Client) log(r *ForecastRequest, reqURL string, fr ForecastResponse) {
+	if c == nil || c.logger == nil {
+		return
+	}
+
+	if u, err := url.Parse(reqURL); err == nil {
+		reqURL = sanitizeURL(u)
+	}
+
+	c.logger(RequestLog{
+		URL:           reqURL,
+		CorrelationID: r.correlation,
+		StatusCode:    fr.StatusCode,
+		APICallCount:  fr.APICallCount,
+		Duration:      fr.Duration,
+		Error:         fr.Error,
+	})
+}
@@ -0,0 +1,69 @@
+package darksky
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestForecastRequest_WithCorrelationID(t *testing.T) {
+	var header http.Header
+
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		validForecastHandler(resp, req)
+	})
+
+	usingTestServer(handler, func(testURL string) {
+		var mu sync.Mutex
+		var logs []RequestLog
+
+		client := NewClient("secret_key").WithBaseURL(testURL).WithLogger(func(l RequestLog) {
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, l)
+		})
+
+		resp := client.MakeRequest(41.8781, -87.6297).WithCorrelationID("trace-1").WithCorrelationID("trace-2").Get()
+		if resp.Error != nil {
+			t.Fatal(resp.Error)
+		}
+
+		if header.Get(CorrelationIDHeader) != "trace-2" || len(header.Values(CorrelationIDHeader)) != 1 {
+			t.Errorf("Expected only the last correlation ID to be sent, got %v.", header.Values(CorrelationIDHeader))
+		}
+
+		client.MakeRequest(41.8781, -87.6297).Get()
+
+		if header.Get(CorrelationIDHeader) != "" {
+			t.Errorf("Expected no correlation ID header by default, got %v.", header.Get(CorrelationIDHeader))
+		}
+
+		if len(logs) != 2 {
+			t.Fatalf("Expected 2 logged calls, got %v.", len(logs))
+		}
+
+		if logs[0].CorrelationID != "trace-2" || logs[0].StatusCode != 200 || logs[0].APICallCount != 1 || logs[0].Duration <= 0 {
+			t.Errorf("Unexpected RequestLog: %+v", logs[0])
+		}
+
+		if logs[1].CorrelationID != "" {
+			t.Errorf("Expected an empty correlation ID by default, got %v.", logs[1].CorrelationID)
+		}
+
+		if strings.Contains(logs[0].URL, "secret_key") || !strings.Contains(logs[0].URL, "/KEY/41.8781,-87.6297") {
+			t.Errorf("Expected the API key to be removed from the logged URL, got %v.", logs[0].URL)
+		}
+	})
+
+	usingTestServer(errorForecastHandler, func(testURL string) {
+		var logged RequestLog
+
+		NewClient(key).WithBaseURL(testURL).WithLogger(func(l RequestLog) { logged = l }).MakeRequest(41.8781, -87.6297).Get()
+
+		if logged.StatusCode != 500 || logged.Error == nil {
+			t.Errorf("Expected failed calls to be logged, got %+v.", logged)
+		}
+	})
+}
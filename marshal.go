@@ -0,0 +1,130 @@
+package darksky
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RFC3339Forecast is a Forecast that marshals all of its time fields as RFC3339 strings in the
+// forecast's timezone, instead of seconds since epoch. Useful when handing a forecast to a
+// JavaScript frontend. Time fields with a zero value are omitted. Only marshalling is supported.
+//
+//	data, err := json.Marshal(darksky.RFC3339Forecast(forecast))
+type RFC3339Forecast Forecast
+
+// MarshalJSON renders the forecast as JSON with RFC3339 formatted time fields.
+func (r RFC3339Forecast) MarshalJSON() ([]byte, error) {
+	f := Forecast(r)
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	formatTimes(v, f.location())
+
+	return json.Marshal(v)
+}
+
+// Project marshals the forecast to JSON keeping only the named fields of each data point, in the
+// currently, minutely, hourly and daily blocks, to reduce the size of cached forecasts. Fields are
+// named by their JSON keys in the API response, such as "time" and "temperature". The rest of the
+// forecast, including block summaries, alerts and flags, is kept as-is. An error is returned for
+// names that aren't DataPoint fields.
+//
+//	data, err := forecast.Project("time", "temperature", "precipProbability")
+func (f *Forecast) Project(fields ...string) ([]byte, error) {
+	known := jsonFieldNames(reflect.TypeOf(DataPoint{}))
+	keep := map[string]bool{}
+
+	for _, field := range fields {
+		if !known[field] {
+			return nil, fmt.Errorf("unknown data point field %q", field)
+		}
+
+		keep[field] = true
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var v map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	project := func(dp interface{}) {
+		if dp, ok := dp.(map[string]interface{}); ok {
+			for key := range dp {
+				if !keep[key] {
+					delete(dp, key)
+				}
+			}
+		}
+	}
+
+	project(v["currently"])
+
+	for _, name := range []string{"minutely", "hourly", "daily"} {
+		if block, ok := v[name].(map[string]interface{}); ok {
+			points, _ := block["data"].([]interface{})
+			for _, dp := range points {
+				project(dp)
+			}
+		}
+	}
+
+	return json.Marshal(v)
+}
+
+// formatTimes walks a decoded JSON value, replacing every epoch time field with its RFC3339 form.
+func formatTimes(v interface{}, loc *time.Location) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			n, ok := value.(json.Number)
+			if !ok || !isTimeField(key) {
+				formatTimes(value, loc)
+				continue
+			}
+
+			secs, err := n.Int64()
+			if err != nil {
+				continue
+			}
+
+			if secs == 0 {
+				delete(v, key)
+				continue
+			}
+
+			v[key] = time.Unix(secs, 0).In(loc).Format(time.RFC3339)
+		}
+	case []interface{}:
+		for _, value := range v {
+			formatTimes(value, loc)
+		}
+	}
+}
+
+// isTimeField reports whether the JSON key holds seconds since epoch.
+func isTimeField(key string) bool {
+	return key == "time" || key == "expires" || strings.HasSuffix(key, "Time")
+}
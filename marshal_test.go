@@ -0,0 +1,91 @@
+package darksky
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRFC3339Forecast_MarshalJSON(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	data, err := json.Marshal(RFC3339Forecast(*forecast))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v struct {
+		Currently map[string]interface{} `json:"currently"`
+		Daily     struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"daily"`
+		Alerts []map[string]interface{} `json:"alerts"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Currently["time"] != "2015-12-28T22:17:05-06:00" {
+		t.Errorf("Expected currently.time to be RFC3339 in Chicago time, was %v.", v.Currently["time"])
+	}
+
+	if _, ok := v.Currently["sunriseTime"]; ok {
+		t.Error("Expected zero valued sunriseTime to be omitted.")
+	}
+
+	if _, ok := v.Daily.Data[0]["sunriseTime"].(string); !ok {
+		t.Errorf("Expected daily sunriseTime to be a string, was %v.", v.Daily.Data[0]["sunriseTime"])
+	}
+
+	if _, ok := v.Alerts[0]["expires"].(string); !ok {
+		t.Errorf("Expected alert expires to be a string, was %v.", v.Alerts[0]["expires"])
+	}
+
+	if v.Currently["temperature"] != 37.57 {
+		t.Errorf("Expected temperature to be unchanged, was %v.", v.Currently["temperature"])
+	}
+}
+
+func TestForecast_Project(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	data, err := forecast.Project("time", "temperature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, _ := json.Marshal(forecast)
+	if len(data) >= len(full)/2 {
+		t.Errorf("Expected the projection to be much smaller, was %v of %v bytes.", len(data), len(full))
+	}
+
+	projected, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if projected.Currently.Time != forecast.Currently.Time || projected.Currently.Temperature != forecast.Currently.Temperature {
+		t.Errorf("Expected time and temperature to be kept, got %+v.", projected.Currently)
+	}
+
+	if projected.Hourly.Data[5].Temperature != forecast.Hourly.Data[5].Temperature || projected.Hourly.Data[5].Humidity != 0 {
+		t.Errorf("Expected only time and temperature in each hourly point, got %+v.", projected.Hourly.Data[5])
+	}
+
+	if projected.Hourly.Summary != forecast.Hourly.Summary || len(projected.Alerts) != 3 || projected.Flags.Units != "us" {
+		t.Error("Expected block summaries, alerts and flags to be kept.")
+	}
+
+	var currently map[string]interface{}
+	json.Unmarshal(data, &struct {
+		Currently *map[string]interface{} `json:"currently"`
+	}{&currently})
+
+	if len(currently) != 2 {
+		t.Errorf("Expected only 2 fields in currently, got %v.", currently)
+	}
+
+	if _, err := forecast.Project("time", "temp"); err == nil {
+		t.Error("Expected an unknown field to be an error.")
+	}
+}
@@ -0,0 +1,41 @@
+package darksky
+
+import (
+	"math"
+	"reflect"
+)
+
+// Sanitize replaces every NaN or infinite float in the forecast, its data points, alerts and flags
+// with 0, so it can be safely re-encoded with encoding/json, which fails on those values. Negative
+// zero is also replaced with 0, so it doesn't render as "-0". Safe to call on a nil Forecast.
+func (f *Forecast) Sanitize() {
+	if f == nil {
+		return
+	}
+
+	sanitizeValue(reflect.ValueOf(f).Elem())
+}
+
+// sanitizeValue walks v, zeroing any float that isn't a finite, non-negative-zero number.
+func sanitizeValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) || (f == 0 && math.Signbit(f)) {
+			v.SetFloat(0)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			sanitizeValue(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				sanitizeValue(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			sanitizeValue(v.Index(i))
+		}
+	}
+}
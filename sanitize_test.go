@@ -0,0 +1,42 @@
+package darksky
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestForecast_Sanitize(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+	original := forecast.Currently.Temperature
+
+	forecast.Currently.Ozone = math.NaN()
+	forecast.Hourly.Data[3].WindSpeed = math.Inf(1)
+	forecast.Daily.Data[7].TemperatureMin = math.Inf(-1)
+	forecast.Minutely.Data[0].PrecipIntensity = math.Copysign(0, -1)
+
+	if _, err := json.Marshal(forecast); err == nil {
+		t.Fatal("Expected NaN to fail to marshal.")
+	}
+
+	forecast.Sanitize()
+
+	if forecast.Currently.Ozone != 0 || forecast.Hourly.Data[3].WindSpeed != 0 || forecast.Daily.Data[7].TemperatureMin != 0 {
+		t.Error("Expected NaN and infinite values to be replaced with 0.")
+	}
+
+	if math.Signbit(forecast.Minutely.Data[0].PrecipIntensity) {
+		t.Error("Expected negative zero to be replaced with 0.")
+	}
+
+	if forecast.Currently.Temperature != original {
+		t.Error("Expected finite values to be unchanged.")
+	}
+
+	if _, err := json.Marshal(forecast); err != nil {
+		t.Errorf("Expected the sanitized forecast to marshal, got %v.", err)
+	}
+
+	var nilForecast *Forecast
+	nilForecast.Sanitize()
+}
@@ -0,0 +1,328 @@
+package darksky
+
+import (
+	"fmt"
+	"math"
+)
+
+// metersPerMile is the number of meters in an international mile.
+const metersPerMile = 1609.344
+
+// ResolvedUnits returns the units system the forecast's values are in, as reported by the API in
+// Flags.Units. For a forecast requested with AUTO this is the system chosen for the location. US,
+// the API's default, is returned when the flags are missing.
+func (f *Forecast) ResolvedUnits() Units {
+	if f == nil || f.Flags == nil || f.Flags.Units == "" {
+		return US
+	}
+
+	return Units(f.Flags.Units)
+}
+
+// EffectiveUnits returns the units system the response's forecast is in, as reported by the API in
+// Flags.Units, which is how each response to an AUTO request reports the system chosen for its
+// location. The bool is false, and US returned, when the flags are missing or the units aren't a
+// known units system.
+func (fr ForecastResponse) EffectiveUnits() (Units, bool) {
+	u := fr.Forecast.ResolvedUnits()
+	if u == AUTO || fr.Forecast.Flags == nil || fr.Forecast.Flags.Units == "" {
+		return US, false
+	}
+
+	if _, ok := unitLabels[u]; !ok {
+		return US, false
+	}
+
+	return u, true
+}
+
+// UnitLabels holds the unit label for each kind of measurement in a units system.
+type UnitLabels struct {
+	Temperature     string
+	Speed           string
+	Distance        string
+	PrecipIntensity string
+	Pressure        string
+}
+
+// unitLabels maps each units system to its labels, following the Dark Sky API documentation.
+var unitLabels = map[Units]UnitLabels{
+	US:  {Temperature: "°F", Speed: "mph", Distance: "mi", PrecipIntensity: "in/hr", Pressure: "mb"},
+	SI:  {Temperature: "°C", Speed: "m/s", Distance: "km", PrecipIntensity: "mm/hr", Pressure: "hPa"},
+	CA:  {Temperature: "°C", Speed: "km/h", Distance: "km", PrecipIntensity: "mm/hr", Pressure: "hPa"},
+	UK:  {Temperature: "°C", Speed: "mph", Distance: "km", PrecipIntensity: "mm/hr", Pressure: "hPa"},
+	UK2: {Temperature: "°C", Speed: "mph", Distance: "mi", PrecipIntensity: "mm/hr", Pressure: "hPa"},
+}
+
+// Labels returns the unit labels for every kind of measurement in the units system:
+//
+//	      Temperature  Speed  Distance  PrecipIntensity  Pressure
+//	US    °F           mph    mi        in/hr            mb
+//	SI    °C           m/s    km        mm/hr            hPa
+//	CA    °C           km/h   km        mm/hr            hPa
+//	UK    °C           mph    km        mm/hr            hPa
+//	UK2   °C           mph    mi        mm/hr            hPa
+//
+// Empty labels are returned for AUTO, which must first be resolved with Forecast.ResolvedUnits,
+// and for unknown units.
+func (u Units) Labels() UnitLabels {
+	return unitLabels[u]
+}
+
+// TemperatureSymbol returns the unit label for temperatures in the units system. See Labels.
+func (u Units) TemperatureSymbol() string {
+	return u.Labels().Temperature
+}
+
+// SpeedUnit returns the unit label for wind speeds in the units system. See Labels.
+func (u Units) SpeedUnit() string {
+	return u.Labels().Speed
+}
+
+// DistanceUnit returns the unit label for distances such as visibility in the units system. See Labels.
+func (u Units) DistanceUnit() string {
+	return u.Labels().Distance
+}
+
+// distanceInMiles reports whether distances such as Visibility are in miles for u, otherwise they
+// are in kilometers. Unknown units, including an unresolved AUTO, are treated as US.
+func distanceInMiles(u Units) bool {
+	switch u {
+	case SI, CA, UK:
+		return false
+	default:
+		return true
+	}
+}
+
+// VisibilityMeters returns Visibility in meters, where u is the units system the data point is in,
+// typically Forecast.ResolvedUnits. Visibility is in miles for US and UK2, and in kilometers for
+// SI, CA and UK. Any other units, including AUTO, are treated as US.
+func (dp DataPoint) VisibilityMeters(u Units) float64 {
+	if distanceInMiles(u) {
+		return dp.Visibility * metersPerMile
+	}
+
+	return dp.Visibility * 1000
+}
+
+// VisibilityKm returns Visibility in kilometers, where u is the units system the data point is in.
+// See VisibilityMeters for the source units assumed.
+func (dp DataPoint) VisibilityKm(u Units) float64 {
+	return dp.VisibilityMeters(u) / 1000
+}
+
+// VisibilityMiles returns Visibility in miles, where u is the units system the data point is in.
+// See VisibilityMeters for the source units assumed.
+func (dp DataPoint) VisibilityMiles(u Units) float64 {
+	return dp.VisibilityMeters(u) / metersPerMile
+}
+
+// speedMs converts a speed in the units system u to meters per second. Speeds are in meters per
+// second for SI, kilometers per hour for CA, and miles per hour for US, UK and UK2. Unknown units,
+// including an unresolved AUTO, are treated as US.
+func speedMs(v float64, u Units) float64 {
+	switch u {
+	case SI:
+		return v
+	case CA:
+		return v / 3.6
+	default:
+		return v * metersPerMile / 3600
+	}
+}
+
+// WindSpeedMs returns WindSpeed in meters per second, where u is the units system the data point
+// is in, typically Forecast.ResolvedUnits. WindSpeed is in meters per second for SI, kilometers per
+// hour for CA, and miles per hour for US, UK and UK2. Any other units are treated as US.
+func (dp DataPoint) WindSpeedMs(u Units) float64 {
+	return speedMs(dp.WindSpeed, u)
+}
+
+// WindSpeedKmh returns WindSpeed in kilometers per hour, where u is the units system the data point
+// is in. See WindSpeedMs for the source units assumed.
+func (dp DataPoint) WindSpeedKmh(u Units) float64 {
+	return speedMs(dp.WindSpeed, u) * 3.6
+}
+
+// WindSpeedMph returns WindSpeed in miles per hour, where u is the units system the data point is
+// in. See WindSpeedMs for the source units assumed.
+func (dp DataPoint) WindSpeedMph(u Units) float64 {
+	return speedMs(dp.WindSpeed, u) * 3600 / metersPerMile
+}
+
+// beaufortScale holds the lowest wind speed, in meters per second, of Beaufort forces 1 through 12.
+var beaufortScale = []float64{0.5, 1.6, 3.4, 5.5, 8.0, 10.8, 13.9, 17.2, 20.8, 24.5, 28.5, 32.7}
+
+// Beaufort returns the Beaufort force, from 0 to 12, for WindSpeed, where u is the units system the
+// data point is in. The speed is converted to meters per second (see WindSpeedMs) and compared to
+// the standard WMO thresholds:
+//
+//	0  calm              below 0.5 m/s
+//	1  light air         0.5 m/s
+//	2  light breeze      1.6 m/s
+//	3  gentle breeze     3.4 m/s
+//	4  moderate breeze   5.5 m/s
+//	5  fresh breeze      8.0 m/s
+//	6  strong breeze     10.8 m/s
+//	7  near gale         13.9 m/s
+//	8  gale              17.2 m/s
+//	9  strong gale       20.8 m/s
+//	10 storm             24.5 m/s
+//	11 violent storm     28.5 m/s
+//	12 hurricane force   32.7 m/s and above
+func (dp DataPoint) Beaufort(u Units) int {
+	ms := dp.WindSpeedMs(u)
+
+	force := 0
+	for _, threshold := range beaufortScale {
+		if ms < threshold {
+			break
+		}
+		force++
+	}
+
+	return force
+}
+
+// precipIntensityLevels are the lower bounds, in inches per hour, of each precipitation intensity
+// description, following the Dark Sky API documentation.
+var precipIntensityLevels = []struct {
+	inches      float64
+	description string
+}{
+	{0.4, "heavy"},
+	{0.1, "moderate"},
+	{0.017, "light"},
+	{0.002, "very light"},
+}
+
+// PrecipIntensityDescription describes PrecipIntensity, given in the units system u, combined with
+// PrecipType, such as "light snow". When PrecipType is missing "precipitation" is used instead.
+// The thresholds are:
+//
+//	             in/hr    mm/hr
+//	very light   0.002    0.051
+//	light        0.017    0.432
+//	moderate     0.1      2.54
+//	heavy        0.4      10.16
+//
+// An empty string is returned below the very light threshold. Unknown units, including an
+// unresolved AUTO, are treated as US.
+func (dp DataPoint) PrecipIntensityDescription(u Units) string {
+	inches := dp.PrecipIntensity
+	if u.Labels().PrecipIntensity == "mm/hr" {
+		inches /= 25.4
+	}
+
+	precipType := dp.PrecipType
+	if precipType == "" {
+		precipType = "precipitation"
+	}
+
+	for _, level := range precipIntensityLevels {
+		if inches >= level.inches {
+			return level.description + " " + precipType
+		}
+	}
+
+	return ""
+}
+
+// IsBelowFreezing reports whether Temperature, given in the units system u, is below the freezing
+// point of water: 32°F for US, and 0°C for every other known units system. Unknown units, including
+// an unresolved AUTO, are treated as US.
+func (dp DataPoint) IsBelowFreezing(u Units) bool {
+	return toFahrenheit(dp.Temperature, u) < 32
+}
+
+// toFahrenheit converts a temperature in the units system u to degrees Fahrenheit. Temperatures are
+// in Fahrenheit for US and Celsius for every other known units system. Unknown units, including an
+// unresolved AUTO, are treated as US.
+func toFahrenheit(v float64, u Units) float64 {
+	if u.TemperatureSymbol() == "°C" {
+		return v*9/5 + 32
+	}
+
+	return v
+}
+
+// fromFahrenheit converts a temperature in degrees Fahrenheit to the units system u.
+func fromFahrenheit(v float64, u Units) float64 {
+	if u.TemperatureSymbol() == "°C" {
+		return (v - 32) * 5 / 9
+	}
+
+	return v
+}
+
+// heatIndex returns the NWS heat index, using the Rothfusz regression, for a temperature in degrees
+// Fahrenheit and relative humidity as a 0 to 1 fraction.
+func heatIndex(t float64, humidity float64) float64 {
+	rh := humidity * 100
+
+	return -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+		0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+}
+
+// windChill returns the NWS wind chill for a temperature in degrees Fahrenheit and wind speed in
+// miles per hour.
+func windChill(t float64, mph float64) float64 {
+	v := math.Pow(mph, 0.16)
+	return 35.74 + 0.6215*t - 35.75*v + 0.4275*t*v
+}
+
+// apparentFahrenheit returns the heat index when it's hot (80°F or above) or the wind chill when
+// it's cold and windy (50°F or below, with wind of at least 3 mph), along with a note naming which
+// was used, for a temperature in degrees Fahrenheit, relative humidity as a 0 to 1 fraction and wind
+// speed in miles per hour. ok is false when neither applies.
+func apparentFahrenheit(t float64, humidity float64, mph float64) (feelsLike float64, note string, ok bool) {
+	switch {
+	case t >= 80:
+		return heatIndex(t, humidity), "heat index", true
+	case t <= 50 && mph >= 3:
+		return windChill(t, mph), "wind chill", true
+	}
+
+	return 0, "", false
+}
+
+// ComputeApparentTemperature returns ApparentTemperature, or when it's missing, as in some Time
+// Machine responses, derives it from Temperature, Humidity and WindSpeed, in the units system u:
+//
+//	80°F / 26.7°C or above                           NWS heat index (Rothfusz regression)
+//	50°F / 10°C or below, with wind of 3 mph or more  NWS wind chill
+//	otherwise                                        Temperature
+//
+// The calculation is done in Fahrenheit and converted back to u. Since the API doesn't distinguish
+// a missing value from zero, an ApparentTemperature of exactly 0 is treated as missing. Unknown
+// units, including an unresolved AUTO, are treated as US.
+func (dp DataPoint) ComputeApparentTemperature(u Units) float64 {
+	if dp.ApparentTemperature != 0 {
+		return dp.ApparentTemperature
+	}
+
+	if f, _, ok := apparentFahrenheit(toFahrenheit(dp.Temperature, u), dp.Humidity, dp.WindSpeedMph(u)); ok {
+		return fromFahrenheit(f, u)
+	}
+
+	return dp.Temperature
+}
+
+// FeelsLikeSummary describes how warm it feels, where u is the units system the data point is in.
+// The heat index is used when it's hot (80°F / 26.7°C or above), the wind chill when it's cold and
+// windy (50°F / 10°C or below, with wind of at least 3 mph), and ApparentTemperature otherwise. The
+// value is rounded and labelled with the units' temperature symbol. (ex: "Feels like 20°F (wind chill)")
+func (dp DataPoint) FeelsLikeSummary(u Units) string {
+	if u.TemperatureSymbol() == "" {
+		u = US
+	}
+
+	feelsLike, note := dp.ApparentTemperature, ""
+
+	if f, n, ok := apparentFahrenheit(toFahrenheit(dp.Temperature, u), dp.Humidity, dp.WindSpeedMph(u)); ok {
+		feelsLike, note = fromFahrenheit(f, u), " ("+n+")"
+	}
+
+	return fmt.Sprintf("Feels like %v%v%v", math.Floor(feelsLike+0.5), u.TemperatureSymbol(), note)
+}
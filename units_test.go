@@ -0,0 +1,344 @@
+package darksky
+
+import (
+	"math"
+	"testing"
+)
+
+func TestForecast_ResolvedUnits(t *testing.T) {
+	var forecast *Forecast
+
+	if forecast.ResolvedUnits() != US {
+		t.Errorf("Expected a nil Forecast to default to US, was %v.", forecast.ResolvedUnits())
+	}
+
+	forecast = loadForecast(t, "testdata/chicago_forecast.json")
+
+	if forecast.ResolvedUnits() != US {
+		t.Errorf("Expected US, was %v.", forecast.ResolvedUnits())
+	}
+
+	forecast.Flags.Units = "ca"
+
+	if forecast.ResolvedUnits() != CA {
+		t.Errorf("Expected CA, was %v.", forecast.ResolvedUnits())
+	}
+}
+
+func TestForecastRequest_AutoUnits(t *testing.T) {
+	req := MakeRequest("foo", 41.1234, -81.1234).WithUnits(AUTO)
+
+	u, err := req.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u != "https://api.darksky.net/forecast/foo/41.1234,-81.1234?lang=en&units=auto" {
+		t.Errorf("Expected AUTO to be passed through, got %v.", u)
+	}
+
+	resp := ForecastResponse{Forecast: Forecast{Flags: &Flags{Units: "si"}}}
+
+	if resp.Forecast.ResolvedUnits() != SI || resp.TemperatureLabel() != "°C" {
+		t.Errorf("Expected resolved SI units in °C, got %v in %v.", resp.Forecast.ResolvedUnits(), resp.TemperatureLabel())
+	}
+
+	resp.Forecast.Flags.Units = "us"
+
+	if resp.TemperatureLabel() != "°F" {
+		t.Errorf("Expected resolved US units in °F, got %v.", resp.TemperatureLabel())
+	}
+}
+
+func TestForecastResponse_EffectiveUnits(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected Units
+	}{
+		{"testdata/chicago_forecast.json", US},
+		{"testdata/toronto_forecast.json", CA},
+	}
+
+	for _, test := range tests {
+		resp := ForecastResponse{Forecast: *loadForecast(t, test.path)}
+
+		if u, ok := resp.EffectiveUnits(); u != test.expected || !ok {
+			t.Errorf("Expected %v to be in %v, got %v, %v.", test.path, test.expected, u, ok)
+		}
+	}
+
+	for _, flags := range []*Flags{nil, {}, {Units: "auto"}, {Units: "metric"}} {
+		resp := ForecastResponse{Forecast: Forecast{Flags: flags}}
+
+		if u, ok := resp.EffectiveUnits(); u != US || ok {
+			t.Errorf("Expected %+v to be unresolvable, got %v, %v.", flags, u, ok)
+		}
+	}
+}
+
+func TestUnits_Symbols(t *testing.T) {
+	tests := []struct {
+		units       Units
+		temperature string
+		speed       string
+		distance    string
+	}{
+		{US, "°F", "mph", "mi"},
+		{SI, "°C", "m/s", "km"},
+		{CA, "°C", "km/h", "km"},
+		{UK, "°C", "mph", "km"},
+		{UK2, "°C", "mph", "mi"},
+		{AUTO, "", "", ""},
+	}
+
+	for _, test := range tests {
+		if test.units.TemperatureSymbol() != test.temperature {
+			t.Errorf("Expected %v temperature symbol to be %v, was %v.", test.units, test.temperature, test.units.TemperatureSymbol())
+		}
+
+		if test.units.SpeedUnit() != test.speed {
+			t.Errorf("Expected %v speed unit to be %v, was %v.", test.units, test.speed, test.units.SpeedUnit())
+		}
+
+		if test.units.DistanceUnit() != test.distance {
+			t.Errorf("Expected %v distance unit to be %v, was %v.", test.units, test.distance, test.units.DistanceUnit())
+		}
+	}
+}
+
+func TestUnits_Labels(t *testing.T) {
+	tests := map[Units]UnitLabels{
+		US:   {"°F", "mph", "mi", "in/hr", "mb"},
+		SI:   {"°C", "m/s", "km", "mm/hr", "hPa"},
+		CA:   {"°C", "km/h", "km", "mm/hr", "hPa"},
+		UK:   {"°C", "mph", "km", "mm/hr", "hPa"},
+		UK2:  {"°C", "mph", "mi", "mm/hr", "hPa"},
+		AUTO: {},
+	}
+
+	for units, expected := range tests {
+		if units.Labels() != expected {
+			t.Errorf("Expected %v labels to be %+v, was %+v.", units, expected, units.Labels())
+		}
+	}
+}
+
+func TestDataPoint_Visibility(t *testing.T) {
+	dp := DataPoint{Visibility: 10}
+
+	tests := []struct {
+		units  Units
+		meters float64
+	}{
+		{US, 16093.44},
+		{UK2, 16093.44},
+		{SI, 10000},
+		{CA, 10000},
+		{UK, 10000},
+	}
+
+	for _, test := range tests {
+		if !closeTo(dp.VisibilityMeters(test.units), test.meters) {
+			t.Errorf("Expected %v visibility of 10 to be %vm, was %v.", test.units, test.meters, dp.VisibilityMeters(test.units))
+		}
+
+		if !closeTo(dp.VisibilityKm(test.units), test.meters/1000) {
+			t.Errorf("Expected %v visibility of 10 to be %vkm, was %v.", test.units, test.meters/1000, dp.VisibilityKm(test.units))
+		}
+
+		if !closeTo(dp.VisibilityMiles(test.units), test.meters/1609.344) {
+			t.Errorf("Expected %v visibility of 10 to be %vmi, was %v.", test.units, test.meters/1609.344, dp.VisibilityMiles(test.units))
+		}
+	}
+}
+
+func closeTo(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestDataPoint_WindSpeed(t *testing.T) {
+	tests := []struct {
+		units     Units
+		windSpeed float64
+		ms        float64
+	}{
+		{US, 10, 4.4704},
+		{UK, 10, 4.4704},
+		{UK2, 10, 4.4704},
+		{SI, 10, 10},
+		{CA, 36, 10},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{WindSpeed: test.windSpeed}
+
+		if !closeTo(dp.WindSpeedMs(test.units), test.ms) {
+			t.Errorf("Expected %v wind speed of %v to be %vm/s, was %v.", test.units, test.windSpeed, test.ms, dp.WindSpeedMs(test.units))
+		}
+
+		if !closeTo(dp.WindSpeedKmh(test.units), test.ms*3.6) {
+			t.Errorf("Expected %v wind speed of %v to be %vkm/h, was %v.", test.units, test.windSpeed, test.ms*3.6, dp.WindSpeedKmh(test.units))
+		}
+
+		if !closeTo(dp.WindSpeedMph(test.units), test.ms/0.44704) {
+			t.Errorf("Expected %v wind speed of %v to be %vmph, was %v.", test.units, test.windSpeed, test.ms/0.44704, dp.WindSpeedMph(test.units))
+		}
+	}
+}
+
+func TestDataPoint_Beaufort(t *testing.T) {
+	tests := []struct {
+		units     Units
+		windSpeed float64
+		force     int
+	}{
+		{SI, 0, 0},
+		{SI, 0.49, 0},
+		{SI, 0.5, 1},
+		{SI, 1.6, 2},
+		{SI, 3.4, 3},
+		{SI, 5.5, 4},
+		{SI, 8.0, 5},
+		{SI, 10.8, 6},
+		{SI, 13.9, 7},
+		{SI, 17.2, 8},
+		{SI, 20.8, 9},
+		{SI, 24.5, 10},
+		{SI, 28.5, 11},
+		{SI, 32.69, 11},
+		{SI, 32.7, 12},
+		{SI, 60, 12},
+		{US, 7.02, 2},
+		{CA, 40, 6},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{WindSpeed: test.windSpeed}
+
+		if dp.Beaufort(test.units) != test.force {
+			t.Errorf("Expected %v wind speed of %v to be force %v, was %v.", test.units, test.windSpeed, test.force, dp.Beaufort(test.units))
+		}
+	}
+}
+
+func TestDataPoint_PrecipIntensityDescription(t *testing.T) {
+	tests := []struct {
+		units      Units
+		intensity  float64
+		precipType string
+		expected   string
+	}{
+		{US, 0, "", ""},
+		{US, 0.0019, "rain", ""},
+		{US, 0.002, "rain", "very light rain"},
+		{US, 0.017, "rain", "light rain"},
+		{US, 0.0999, "snow", "light snow"},
+		{US, 0.1, "snow", "moderate snow"},
+		{US, 0.4, "sleet", "heavy sleet"},
+		{US, 0.05, "", "light precipitation"},
+		{SI, 0.05, "rain", ""},
+		{SI, 0.0508, "rain", "very light rain"},
+		{SI, 2.54, "rain", "moderate rain"},
+		{CA, 10.16, "rain", "heavy rain"},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{PrecipIntensity: test.intensity, PrecipType: test.precipType}
+
+		if dp.PrecipIntensityDescription(test.units) != test.expected {
+			t.Errorf("Expected %v intensity of %v to be %q, was %q.", test.units, test.intensity, test.expected, dp.PrecipIntensityDescription(test.units))
+		}
+	}
+}
+
+func TestDataPoint_IsBelowFreezing(t *testing.T) {
+	tests := []struct {
+		units       Units
+		temperature float64
+		expected    bool
+	}{
+		{US, 31.9, true},
+		{US, 32, false},
+		{US, 1, true},
+		{US, 40, false},
+		{SI, -0.1, true},
+		{SI, 0, false},
+		{SI, 1, false},
+		{CA, -3.2, true},
+		{UK2, 5, false},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{Temperature: test.temperature}
+
+		if dp.IsBelowFreezing(test.units) != test.expected {
+			t.Errorf("Expected %v temperature of %v below freezing to be %v.", test.units, test.temperature, test.expected)
+		}
+	}
+}
+
+func TestDataPoint_ComputeApparentTemperature(t *testing.T) {
+	forecast := loadForecast(t, "testdata/chicago_forecast.json")
+
+	for _, dp := range forecast.Hourly.Data {
+		provided := dp.ApparentTemperature
+
+		if dp.ComputeApparentTemperature(US) != provided {
+			t.Errorf("Expected the provided value to be returned, got %v.", dp.ComputeApparentTemperature(US))
+		}
+
+		dp.ApparentTemperature = 0
+
+		if computed := dp.ComputeApparentTemperature(US); math.Abs(computed-provided) > 0.1 {
+			t.Errorf("Expected the computed wind chill to agree with the API's %v, got %v.", provided, computed)
+		}
+	}
+
+	tests := []struct {
+		units       Units
+		temperature float64
+		humidity    float64
+		windSpeed   float64
+		expected    float64
+	}{
+		{US, 90, 0.6, 5, 99.7},
+		{SI, 32.2222, 0.6, 2.2, 37.6},
+		{US, 65, 0.5, 10, 65},
+		{SI, 20, 0.5, 10, 20},
+		{US, 20, 0.7, 15, 6.2},
+		{SI, -10, 0.7, 5, -17.4},
+	}
+
+	for _, test := range tests {
+		dp := DataPoint{Temperature: test.temperature, Humidity: test.humidity, WindSpeed: test.windSpeed}
+
+		if computed := dp.ComputeApparentTemperature(test.units); math.Abs(computed-test.expected) > 0.1 {
+			t.Errorf("Expected %v apparent temperature of %v, got %v.", test.units, test.expected, computed)
+		}
+	}
+}
+
+func TestDataPoint_FeelsLikeSummary(t *testing.T) {
+	tests := []struct {
+		units    Units
+		dp       DataPoint
+		expected string
+	}{
+		// Hot and humid, NWS heat index chart gives 100°F.
+		{US, DataPoint{Temperature: 90, Humidity: 0.6, ApparentTemperature: 99}, "Feels like 100°F (heat index)"},
+		{SI, DataPoint{Temperature: 32.22, Humidity: 0.6, ApparentTemperature: 37}, "Feels like 38°C (heat index)"},
+		// Cold and windy, NWS wind chill chart gives 19°F.
+		{US, DataPoint{Temperature: 30, WindSpeed: 15, ApparentTemperature: 20}, "Feels like 19°F (wind chill)"},
+		{SI, DataPoint{Temperature: -1.11, WindSpeed: 6.71, ApparentTemperature: -6}, "Feels like -7°C (wind chill)"},
+		// Mild, or cold and calm, uses the apparent temperature.
+		{US, DataPoint{Temperature: 65, WindSpeed: 10, ApparentTemperature: 64.6}, "Feels like 65°F"},
+		{US, DataPoint{Temperature: 30, WindSpeed: 2, ApparentTemperature: 28.2}, "Feels like 28°F"},
+		{CA, DataPoint{Temperature: 18, WindSpeed: 10, ApparentTemperature: 17.6}, "Feels like 18°C"},
+	}
+
+	for _, test := range tests {
+		if test.dp.FeelsLikeSummary(test.units) != test.expected {
+			t.Errorf("Expected %v temperature of %v to be %v, was %v.", test.units, test.dp.Temperature, test.expected, test.dp.FeelsLikeSummary(test.units))
+		}
+	}
+}